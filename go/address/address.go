@@ -0,0 +1,188 @@
+// Package address parses Zcash transparent addresses and builds the
+// scriptPubKey bytes t2z.TransparentInput and t2z.Payment need, so callers no
+// longer have to hand-roll base58check decoding and script templates
+// themselves.
+package address
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// Type identifies which transparent address encoding a parsed address uses.
+type Type int
+
+const (
+	// P2PKH is a standard pay-to-pubkey-hash address (version bytes 0x1c,0xb8
+	// on mainnet - "t1...").
+	P2PKH Type = iota
+	// P2SH is a pay-to-script-hash address (version bytes 0x1c,0xbd on
+	// mainnet - "t3...").
+	P2SH
+)
+
+// mainnet/testnet two-byte version prefixes, as used by zcashd.
+var (
+	mainnetP2PKH = [2]byte{0x1c, 0xb8}
+	mainnetP2SH  = [2]byte{0x1c, 0xbd}
+	testnetP2PKH = [2]byte{0x1d, 0x25}
+	testnetP2SH  = [2]byte{0x1c, 0xba}
+)
+
+// Address is a parsed transparent Zcash address.
+type Address struct {
+	Type      Type
+	Mainnet   bool
+	Hash      [20]byte // hash160 of the pubkey (P2PKH) or redeem script (P2SH)
+	Formatted string
+}
+
+// Parse decodes a base58check-encoded transparent address, identifying its
+// type and network from its two-byte version prefix.
+func Parse(addr string) (*Address, error) {
+	decoded, err := base58CheckDecode(addr)
+	if err != nil {
+		return nil, fmt.Errorf("address: decoding %q: %w", addr, err)
+	}
+	if len(decoded) != 22 {
+		return nil, fmt.Errorf("address: %q is not a transparent address (unexpected length %d)", addr, len(decoded))
+	}
+	var prefix [2]byte
+	copy(prefix[:], decoded[:2])
+
+	a := &Address{Formatted: addr}
+	copy(a.Hash[:], decoded[2:])
+
+	switch prefix {
+	case mainnetP2PKH:
+		a.Type, a.Mainnet = P2PKH, true
+	case mainnetP2SH:
+		a.Type, a.Mainnet = P2SH, true
+	case testnetP2PKH:
+		a.Type, a.Mainnet = P2PKH, false
+	case testnetP2SH:
+		a.Type, a.Mainnet = P2SH, false
+	default:
+		return nil, fmt.Errorf("address: %q has unrecognized version prefix %x", addr, prefix)
+	}
+	return a, nil
+}
+
+// ScriptPubKey builds the scriptPubKey this address would be locked by:
+// `OP_DUP OP_HASH160 <hash> OP_EQUALVERIFY OP_CHECKSIG` for P2PKH, or
+// `OP_HASH160 <hash> OP_EQUAL` for P2SH.
+func (a *Address) ScriptPubKey() []byte {
+	switch a.Type {
+	case P2SH:
+		script := make([]byte, 0, 23)
+		script = append(script, 0xa9, 0x14)
+		script = append(script, a.Hash[:]...)
+		return append(script, 0x87)
+	default: // P2PKH
+		script := make([]byte, 0, 25)
+		script = append(script, 0x76, 0xa9, 0x14)
+		script = append(script, a.Hash[:]...)
+		return append(script, 0x88, 0xac)
+	}
+}
+
+// EncodeP2PKH builds the base58check address string for a P2PKH hash160.
+func EncodeP2PKH(hash [20]byte, mainnet bool) string {
+	prefix := testnetP2PKH
+	if mainnet {
+		prefix = mainnetP2PKH
+	}
+	return encode(prefix, hash)
+}
+
+// EncodeP2SH builds the base58check address string for a P2SH hash160.
+func EncodeP2SH(hash [20]byte, mainnet bool) string {
+	prefix := testnetP2SH
+	if mainnet {
+		prefix = mainnetP2SH
+	}
+	return encode(prefix, hash)
+}
+
+func encode(prefix [2]byte, hash [20]byte) string {
+	payload := append(append([]byte{}, prefix[:]...), hash[:]...)
+	checksum := doubleSHA256(payload)
+	return base58Encode(append(payload, checksum[:4]...))
+}
+
+func doubleSHA256(data []byte) [32]byte {
+	first := sha256.Sum256(data)
+	return sha256.Sum256(first[:])
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func base58Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	n := new(big.Int).SetBytes(data)
+	base := big.NewInt(58)
+	mod := new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		out = append(out, base58Alphabet[0])
+	}
+	reverse(out)
+	return string(out)
+}
+
+func base58CheckDecode(s string) ([]byte, error) {
+	n := new(big.Int)
+	base := big.NewInt(58)
+	for _, c := range s {
+		idx := indexOf(base58Alphabet, byte(c))
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+
+	decoded := n.Bytes()
+	leadingZeros := 0
+	for leadingZeros < len(s) && s[leadingZeros] == base58Alphabet[0] {
+		leadingZeros++
+	}
+	full := make([]byte, leadingZeros+len(decoded))
+	copy(full[leadingZeros:], decoded)
+
+	if len(full) < 4 {
+		return nil, fmt.Errorf("decoded data too short to contain a checksum")
+	}
+	payload, checksum := full[:len(full)-4], full[len(full)-4:]
+	want := doubleSHA256(payload)
+	for i := 0; i < 4; i++ {
+		if checksum[i] != want[i] {
+			return nil, fmt.Errorf("checksum mismatch")
+		}
+	}
+	return payload, nil
+}
+
+func indexOf(alphabet string, c byte) int {
+	for i := 0; i < len(alphabet); i++ {
+		if alphabet[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
@@ -0,0 +1,93 @@
+package address_test
+
+import (
+	"testing"
+
+	"github.com/gstohl/t2z/go/address"
+)
+
+// TestEncodeParseRoundTrip checks that EncodeP2PKH/EncodeP2SH produce
+// addresses Parse recovers with the same type, network, and hash160 - the
+// property ScriptFromPubkey-style callers depend on.
+func TestEncodeParseRoundTrip(t *testing.T) {
+	var hash [20]byte
+	for i := range hash {
+		hash[i] = byte(i + 1)
+	}
+
+	cases := []struct {
+		name    string
+		encode  func([20]byte, bool) string
+		mainnet bool
+		want    address.Type
+	}{
+		{"P2PKH mainnet", address.EncodeP2PKH, true, address.P2PKH},
+		{"P2PKH testnet", address.EncodeP2PKH, false, address.P2PKH},
+		{"P2SH mainnet", address.EncodeP2SH, true, address.P2SH},
+		{"P2SH testnet", address.EncodeP2SH, false, address.P2SH},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := c.encode(hash, c.mainnet)
+			parsed, err := address.Parse(s)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", s, err)
+			}
+			if parsed.Type != c.want {
+				t.Fatalf("Type = %v, want %v", parsed.Type, c.want)
+			}
+			if parsed.Mainnet != c.mainnet {
+				t.Fatalf("Mainnet = %v, want %v", parsed.Mainnet, c.mainnet)
+			}
+			if parsed.Hash != hash {
+				t.Fatalf("Hash = %x, want %x", parsed.Hash, hash)
+			}
+		})
+	}
+}
+
+// TestScriptPubKeyMatchesType checks that ScriptPubKey produces the expected
+// template for each address Type.
+func TestScriptPubKeyMatchesType(t *testing.T) {
+	var hash [20]byte
+	for i := range hash {
+		hash[i] = byte(i + 1)
+	}
+
+	p2pkh, err := address.Parse(address.EncodeP2PKH(hash, false))
+	if err != nil {
+		t.Fatalf("Parse P2PKH: %v", err)
+	}
+	script := p2pkh.ScriptPubKey()
+	if len(script) != 25 || script[0] != 0x76 || script[1] != 0xa9 || script[24] != 0xac {
+		t.Fatalf("P2PKH ScriptPubKey = %x, want OP_DUP OP_HASH160 <20 bytes> OP_EQUALVERIFY OP_CHECKSIG", script)
+	}
+
+	p2sh, err := address.Parse(address.EncodeP2SH(hash, false))
+	if err != nil {
+		t.Fatalf("Parse P2SH: %v", err)
+	}
+	script = p2sh.ScriptPubKey()
+	if len(script) != 23 || script[0] != 0xa9 || script[22] != 0x87 {
+		t.Fatalf("P2SH ScriptPubKey = %x, want OP_HASH160 <20 bytes> OP_EQUAL", script)
+	}
+}
+
+// TestParseRejectsBadChecksum checks that flipping a character in a valid
+// address is caught by the base58check checksum rather than silently
+// decoding to the wrong hash.
+func TestParseRejectsBadChecksum(t *testing.T) {
+	var hash [20]byte
+	s := address.EncodeP2PKH(hash, true)
+	tampered := []byte(s)
+	last := tampered[len(tampered)-1]
+	for _, c := range "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz" {
+		if byte(c) != last {
+			tampered[len(tampered)-1] = byte(c)
+			break
+		}
+	}
+	if _, err := address.Parse(string(tampered)); err == nil {
+		t.Fatal("Parse accepted an address with a tampered checksum")
+	}
+}
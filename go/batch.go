@@ -0,0 +1,62 @@
+package t2z
+
+import "fmt"
+
+// InputSighash is everything a remote signer needs to sign one transparent
+// input without a second round trip back to the PCZT for its metadata: the
+// sighash to sign, which pubkey to sign it with, and the pool/amount it
+// spends from (amount feeds straight back into GetSighash's v5 commitment if
+// the signer ever needs to recompute it itself).
+type InputSighash struct {
+	Index   int
+	Sighash [32]byte
+	Pubkey  []byte
+	Pool    Pool
+	Amount  uint64
+}
+
+// GetAllSighashes returns every transparent input's InputSighash, in order,
+// so an air-gapped device can sign a whole transaction from a single QR
+// handoff instead of shuttling back for each input's pubkey and amount.
+func GetAllSighashes(pczt *PCZT) ([]InputSighash, error) {
+	sighashes := make([]InputSighash, len(pczt.inputs))
+	for i, in := range pczt.inputs {
+		sighash, err := GetSighash(pczt, uint(i))
+		if err != nil {
+			return nil, fmt.Errorf("t2z: getting sighash for input %d: %w", i, err)
+		}
+		sighashes[i] = InputSighash{
+			Index:   i,
+			Sighash: sighash,
+			Pubkey:  in.Pubkey,
+			Pool:    PoolTransparent,
+			Amount:  in.Amount,
+		}
+	}
+	return sighashes, nil
+}
+
+// InputSignature pairs a transparent input's index with the signature for
+// it, as returned by a remote signer working from GetAllSighashes' output.
+type InputSignature struct {
+	Index     int
+	Signature [64]byte
+}
+
+// AppendSignatures appends each of sigs to pczt at its own Index, returning
+// the resulting PCZT. Unlike looping over AppendSignature by hand, sigs may
+// cover only some of pczt's inputs and in any order - e.g. when multiple
+// remote signers each return signatures for a disjoint subset of inputs, or
+// a single signer's responses arrive out of order - since every entry
+// carries its own Index rather than relying on position.
+func AppendSignatures(pczt *PCZT, sigs []InputSignature) (*PCZT, error) {
+	current := pczt
+	for _, s := range sigs {
+		var err error
+		current, err = AppendSignature(current, uint(s.Index), s.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("t2z: appending signature for input %d: %w", s.Index, err)
+		}
+	}
+	return current, nil
+}
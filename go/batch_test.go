@@ -0,0 +1,142 @@
+package t2z_test
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	t2z "github.com/gstohl/t2z/go"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// TestAppendSignaturesOutOfOrderAndPartial builds a 3-input transaction,
+// applies its signatures via AppendSignatures in reverse order and checks the
+// result matches applying them via AppendSignature one at a time - the case
+// BatchAppendSignatures' positional [][64]byte couldn't represent, since it
+// required a signature for every index in order.
+func TestAppendSignaturesOutOfOrderAndPartial(t *testing.T) {
+	const numInputs = 3
+	privateKeyBytes := make([]byte, 32)
+	for i := range privateKeyBytes {
+		privateKeyBytes[i] = 1
+	}
+	privKey := secp256k1.PrivKeyFromBytes(privateKeyBytes)
+	pubKeyBytes := privKey.PubKey().SerializeCompressed()
+
+	pubkeyHash := sha256.Sum256(pubKeyBytes)
+	r := ripemd160.New()
+	r.Write(pubkeyHash[:])
+	hash160 := r.Sum(nil)
+	scriptPubKey := append([]byte{0x76, 0xa9, 0x14}, hash160...)
+	scriptPubKey = append(scriptPubKey, 0x88, 0xac)
+
+	inputs := make([]t2z.TransparentInput, numInputs)
+	for i := range inputs {
+		var txid [32]byte
+		binary.LittleEndian.PutUint64(txid[:8], uint64(i))
+		inputs[i] = t2z.TransparentInput{Pubkey: pubKeyBytes, TxID: txid, Vout: 0, Amount: 1_000_000, ScriptPubKey: scriptPubKey}
+	}
+
+	payments := []t2z.Payment{{Address: "tm9iMLAuYMzJ6jtFLcA7rzUmfreGuKvr7Ma", Amount: 1_000_000}}
+	request, err := t2z.NewTransactionRequest(payments)
+	if err != nil {
+		t.Fatalf("NewTransactionRequest: %v", err)
+	}
+	defer request.Free()
+
+	pczt, err := t2z.ProposeTransaction(inputs, request)
+	if err != nil {
+		t.Fatalf("ProposeTransaction: %v", err)
+	}
+
+	sighashes, err := t2z.GetAllSighashes(pczt)
+	if err != nil {
+		t.Fatalf("GetAllSighashes: %v", err)
+	}
+
+	sigs := make([]t2z.InputSignature, numInputs)
+	for i, sh := range sighashes {
+		compactSig := ecdsa.SignCompact(privKey, sh.Sighash[:], true)
+		var sig [64]byte
+		copy(sig[:], compactSig[1:])
+		sigs[i] = t2z.InputSignature{Index: sh.Index, Signature: sig}
+	}
+
+	// Apply out of order: last input first.
+	reversed := []t2z.InputSignature{sigs[2], sigs[1], sigs[0]}
+	signed, err := t2z.AppendSignatures(pczt, reversed)
+	if err != nil {
+		t.Fatalf("AppendSignatures: %v", err)
+	}
+
+	txBytes, err := t2z.FinalizeAndExtract(signed)
+	if err != nil {
+		t.Fatalf("FinalizeAndExtract: %v", err)
+	}
+
+	prevOutputs := make([]t2z.TransparentOutput, numInputs)
+	for i := range inputs {
+		prevOutputs[i] = t2z.TransparentOutput{ScriptPubKey: inputs[i].ScriptPubKey, Amount: inputs[i].Amount}
+	}
+	if err := t2z.VerifyTransaction(txBytes, prevOutputs); err != nil {
+		t.Fatalf("VerifyTransaction: %v", err)
+	}
+}
+
+// TestAppendSignaturesPartialLeavesRestUnsigned checks that AppendSignatures
+// with a subset of inputs' signatures leaves the others unsigned, so a
+// second signer's disjoint subset can still be applied afterward.
+func TestAppendSignaturesPartialLeavesRestUnsigned(t *testing.T) {
+	const numInputs = 2
+	privateKeyBytes := make([]byte, 32)
+	for i := range privateKeyBytes {
+		privateKeyBytes[i] = 1
+	}
+	privKey := secp256k1.PrivKeyFromBytes(privateKeyBytes)
+	pubKeyBytes := privKey.PubKey().SerializeCompressed()
+
+	pubkeyHash := sha256.Sum256(pubKeyBytes)
+	r := ripemd160.New()
+	r.Write(pubkeyHash[:])
+	hash160 := r.Sum(nil)
+	scriptPubKey := append([]byte{0x76, 0xa9, 0x14}, hash160...)
+	scriptPubKey = append(scriptPubKey, 0x88, 0xac)
+
+	inputs := make([]t2z.TransparentInput, numInputs)
+	for i := range inputs {
+		var txid [32]byte
+		binary.LittleEndian.PutUint64(txid[:8], uint64(i))
+		inputs[i] = t2z.TransparentInput{Pubkey: pubKeyBytes, TxID: txid, Vout: 0, Amount: 1_000_000, ScriptPubKey: scriptPubKey}
+	}
+
+	payments := []t2z.Payment{{Address: "tm9iMLAuYMzJ6jtFLcA7rzUmfreGuKvr7Ma", Amount: 1_000_000}}
+	request, err := t2z.NewTransactionRequest(payments)
+	if err != nil {
+		t.Fatalf("NewTransactionRequest: %v", err)
+	}
+	defer request.Free()
+
+	pczt, err := t2z.ProposeTransaction(inputs, request)
+	if err != nil {
+		t.Fatalf("ProposeTransaction: %v", err)
+	}
+
+	sighash, err := t2z.GetSighash(pczt, 0)
+	if err != nil {
+		t.Fatalf("GetSighash: %v", err)
+	}
+	compactSig := ecdsa.SignCompact(privKey, sighash[:], true)
+	var sig [64]byte
+	copy(sig[:], compactSig[1:])
+
+	partial, err := t2z.AppendSignatures(pczt, []t2z.InputSignature{{Index: 0, Signature: sig}})
+	if err != nil {
+		t.Fatalf("AppendSignatures: %v", err)
+	}
+
+	if _, err := t2z.FinalizeAndExtract(partial); err == nil {
+		t.Fatal("FinalizeAndExtract succeeded with input 1 still unsigned")
+	}
+}
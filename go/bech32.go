@@ -0,0 +1,117 @@
+package t2z
+
+import (
+	"fmt"
+	"strings"
+)
+
+// bech32Charset is the data-part alphabet shared by Bech32 and Bech32m
+// (BIP-173 / BIP-350), used to decode Sapling (Bech32) and unified (Bech32m)
+// addresses.
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Const and bech32mConst are the checksum generator's target residue
+// for plain Bech32 and Bech32m respectively; decodeBech32 tries both and
+// reports which one verified.
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// decodeBech32 decodes s as a Bech32 or Bech32m string, returning its human
+// readable part, the data part as a byte-per-5-bit-group slice, and whether
+// the checksum verified as Bech32m (false meaning plain Bech32).
+func decodeBech32(s string) (hrp string, data []byte, isBech32m bool, err error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, false, fmt.Errorf("mixed-case string is not valid bech32")
+	}
+	lower := strings.ToLower(s)
+
+	sep := strings.LastIndex(lower, "1")
+	if sep < 1 || sep+7 > len(lower) {
+		return "", nil, false, fmt.Errorf("invalid bech32 separator position")
+	}
+	hrp = lower[:sep]
+	dataPart := lower[sep+1:]
+
+	values := make([]int, len(dataPart))
+	for i, c := range dataPart {
+		idx := strings.IndexRune(bech32Charset, c)
+		if idx < 0 {
+			return "", nil, false, fmt.Errorf("invalid bech32 character %q", c)
+		}
+		values[i] = idx
+	}
+
+	checksum := bech32Polymod(append(bech32HRPExpand(hrp), values...))
+	switch checksum {
+	case bech32Const:
+		isBech32m = false
+	case bech32mConst:
+		isBech32m = true
+	default:
+		return "", nil, false, fmt.Errorf("invalid bech32/bech32m checksum")
+	}
+
+	data = make([]byte, len(values)-6)
+	for i, v := range values[:len(values)-6] {
+		data[i] = byte(v)
+	}
+	return hrp, data, isBech32m, nil
+}
+
+func bech32HRPExpand(hrp string) []int {
+	out := make([]int, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, int(hrp[i]>>5))
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, int(hrp[i]&31))
+	}
+	return out
+}
+
+func bech32Polymod(values []int) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// convertBits repacks a slice of fromBits-wide groups (each stored one per
+// byte, as decodeBech32 returns) into a slice of toBits-wide groups. It is
+// used to turn Bech32's 5-bit data-part symbols into 8-bit payload bytes.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc, bits uint32
+	maxVal := uint32(1)<<toBits - 1
+	var out []byte
+	for _, b := range data {
+		if uint32(b)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid data value for %d-bit group", fromBits)
+		}
+		acc = acc<<fromBits | uint32(b)
+		bits += uint32(fromBits)
+		for bits >= uint32(toBits) {
+			bits -= uint32(toBits)
+			out = append(out, byte(acc>>bits)&byte(maxVal))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(uint32(toBits)-bits))&byte(maxVal))
+		}
+	} else if bits >= uint32(fromBits) || byte(acc<<(uint32(toBits)-bits))&byte(maxVal) != 0 {
+		return nil, fmt.Errorf("non-zero padding in bech32 data")
+	}
+	return out, nil
+}
@@ -0,0 +1,148 @@
+package t2z
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gstohl/t2z/go/transport/ur"
+)
+
+// BIP276 is the result of decoding a BIP-276-style envelope: the
+// human-readable prefix that identified its contents, its version and
+// network fields, and the payload itself.
+type BIP276 struct {
+	Prefix  string
+	Network int
+	Version int
+	Data    []byte
+}
+
+// EncodeBIP276 wraps data in a BIP-276-style ASCII envelope -
+// `<prefix>:<network>-<version>-<hex(data)>-<checksum>`, all two-digit
+// decimal fields and lowercase hex, where checksum is the first 4 bytes of
+// SHA256d(prefix || byte(network) || byte(version) || data) - intended for
+// contexts where a single copy-pasteable string is easier to handle than raw
+// binary (config files, clipboards, URLs). MarshalTxText builds on this for
+// finalized transactions specifically; a PCZT's own text envelope is
+// EncodePCZT/DecodePCZT's pczt-zcash format instead, which additionally
+// checksums and network-tags the payload. For animated-QR transport of
+// either kind of payload, see the t2z/transport/ur package.
+func EncodeBIP276(prefix string, network int, version int, data []byte) string {
+	checksum := bip276Checksum(prefix, network, version, data)
+	return fmt.Sprintf("%s:%02d-%02d-%s-%s", prefix, network, version, hex.EncodeToString(data), hex.EncodeToString(checksum[:]))
+}
+
+// DecodeBIP276 reverses EncodeBIP276, verifying the checksum before
+// returning the decoded envelope. It does not check prefix against any
+// expected value; callers that care which kind of payload they got (e.g.
+// PCZT.UnmarshalText) should check BIP276.Prefix themselves.
+func DecodeBIP276(s string) (BIP276, error) {
+	prefix, rest, ok := strings.Cut(s, ":")
+	if !ok {
+		return BIP276{}, fmt.Errorf("t2z: %q is not a BIP-276 envelope", s)
+	}
+	fields := strings.Split(rest, "-")
+	if len(fields) != 4 {
+		return BIP276{}, fmt.Errorf("t2z: %q is not a BIP-276 envelope", s)
+	}
+
+	network, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return BIP276{}, fmt.Errorf("t2z: decoding BIP-276 network field: %w", err)
+	}
+	version, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return BIP276{}, fmt.Errorf("t2z: decoding BIP-276 version field: %w", err)
+	}
+	data, err := hex.DecodeString(fields[2])
+	if err != nil {
+		return BIP276{}, fmt.Errorf("t2z: decoding BIP-276 payload: %w", err)
+	}
+	wantChecksum, err := hex.DecodeString(fields[3])
+	if err != nil || len(wantChecksum) != 4 {
+		return BIP276{}, fmt.Errorf("t2z: decoding BIP-276 checksum: %w", err)
+	}
+
+	gotChecksum := bip276Checksum(prefix, network, version, data)
+	if string(gotChecksum[:]) != string(wantChecksum) {
+		return BIP276{}, fmt.Errorf("t2z: BIP-276 checksum mismatch")
+	}
+
+	return BIP276{Prefix: prefix, Network: network, Version: version, Data: data}, nil
+}
+
+// bip276Checksum computes the first 4 bytes of SHA256d(prefix || byte(network)
+// || byte(version) || data), the checksum EncodeBIP276/DecodeBIP276 append
+// and verify.
+func bip276Checksum(prefix string, network, version int, data []byte) [4]byte {
+	body := append([]byte{byte(network), byte(version)}, data...)
+	first := sha256.Sum256(append([]byte(prefix), body...))
+	second := sha256.Sum256(first[:])
+	var sum [4]byte
+	copy(sum[:], second[:4])
+	return sum
+}
+
+// txTextPrefix identifies a BIP-276 envelope produced by MarshalTxText.
+const txTextPrefix = "tx-zec"
+
+// MarshalTxText wraps txBytes - a finalized transaction as returned by
+// FinalizeAndExtract - in the same kind of BIP-276 envelope as
+// PCZT.MarshalText, so a fully-signed transaction can be handed from an
+// air-gapped signer to an online broadcaster as a shareable string rather
+// than a naked byte slice.
+func MarshalTxText(txBytes []byte) string {
+	return EncodeBIP276(txTextPrefix, 1, 1, txBytes)
+}
+
+// UnmarshalTxText reverses MarshalTxText, returning the finalized
+// transaction's raw bytes.
+func UnmarshalTxText(s string) ([]byte, error) {
+	envelope, err := DecodeBIP276(s)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.Prefix != txTextPrefix {
+		return nil, fmt.Errorf("t2z: %q is not a %s BIP-276 envelope", envelope.Prefix, txTextPrefix)
+	}
+	return envelope.Data, nil
+}
+
+// SplitAnimatedQR serializes pczt and splits it into UR fountain-coded parts
+// of at most maxFragmentLen bytes each, ready to render as an animated QR
+// sequence for an air-gapped signer.
+func SplitAnimatedQR(pczt *PCZT, maxFragmentLen int) ([]string, error) {
+	payload, err := Serialize(pczt)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: serializing PCZT: %w", err)
+	}
+	return ur.NewEncoder().Split(payload, maxFragmentLen), nil
+}
+
+// AnimatedQRDecoder accumulates UR parts scanned from an animated QR sequence
+// and reconstructs the PCZT once enough frames have been seen.
+type AnimatedQRDecoder struct {
+	decoder *ur.Decoder
+}
+
+// NewAnimatedQRDecoder creates an empty AnimatedQRDecoder.
+func NewAnimatedQRDecoder() *AnimatedQRDecoder {
+	return &AnimatedQRDecoder{decoder: ur.NewDecoder()}
+}
+
+// Scan ingests one decoded QR frame's text. It returns done=true and the
+// reconstructed PCZT once enough frames have been seen.
+func (d *AnimatedQRDecoder) Scan(frame string) (done bool, pczt *PCZT, err error) {
+	complete, payload, err := d.decoder.Receive(frame)
+	if err != nil || !complete {
+		return false, nil, err
+	}
+	pczt, err = Parse(payload)
+	if err != nil {
+		return false, nil, fmt.Errorf("t2z: parsing reassembled PCZT: %w", err)
+	}
+	return true, pczt, nil
+}
@@ -0,0 +1,82 @@
+package t2z
+
+import "sort"
+
+// branchAndBoundMaxTries caps how many subsets BranchAndBoundSource explores
+// before giving up and falling back to largest-first, keeping selection time
+// bounded even over a large UTXO set.
+const branchAndBoundMaxTries = 100_000
+
+// BranchAndBoundSource is an InputSource that, like Bitcoin Core's coin
+// selection, first searches for a subset of UTXOs that matches the target
+// almost exactly (within marginalFeeDust) so no change output - and its
+// associated privacy leak and extra fee - is needed at all. If no such subset
+// is found within branchAndBoundMaxTries attempts, it falls back to
+// UTXOPool's largest-first strategy.
+type BranchAndBoundSource struct {
+	utxos    []TransparentInput
+	fallback *UTXOPool
+}
+
+// NewBranchAndBoundSource builds a BranchAndBoundSource over utxos.
+func NewBranchAndBoundSource(utxos []TransparentInput) *BranchAndBoundSource {
+	sorted := append([]TransparentInput{}, utxos...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Amount > sorted[j].Amount })
+	return &BranchAndBoundSource{utxos: sorted, fallback: NewUTXOPool(utxos)}
+}
+
+// SelectInputs implements InputSource.
+func (b *BranchAndBoundSource) SelectInputs(target uint64) ([]TransparentInput, uint64, error) {
+	if indices, total, ok := b.search(target); ok {
+		selected := make([]TransparentInput, len(indices))
+		for i, idx := range indices {
+			selected[i] = b.utxos[idx]
+		}
+		return selected, total, nil
+	}
+	return b.fallback.SelectInputs(target)
+}
+
+// search performs a depth-first branch-and-bound over included/excluded
+// decisions for each (pre-sorted, descending) UTXO, pruning any branch whose
+// running total already exceeds target by more than marginalFeeDust -
+// further inclusions can only grow it further. It returns the first subset
+// found whose total lands in [target, target+marginalFeeDust].
+func (b *BranchAndBoundSource) search(target uint64) (indices []int, total uint64, ok bool) {
+	tries := 0
+	var current []int
+	var currentTotal uint64
+
+	var recurse func(pos int) bool
+	recurse = func(pos int) bool {
+		tries++
+		if tries > branchAndBoundMaxTries {
+			return false
+		}
+		if currentTotal >= target {
+			if currentTotal <= target+marginalFeeDust {
+				indices = append([]int{}, current...)
+				total = currentTotal
+				return true
+			}
+			return false
+		}
+		if pos >= len(b.utxos) {
+			return false
+		}
+
+		// Include utxos[pos].
+		current = append(current, pos)
+		currentTotal += b.utxos[pos].Amount
+		if recurse(pos + 1) {
+			return true
+		}
+		currentTotal -= b.utxos[pos].Amount
+		current = current[:len(current)-1]
+
+		// Exclude utxos[pos] and keep searching.
+		return recurse(pos + 1)
+	}
+
+	return indices, total, recurse(0)
+}
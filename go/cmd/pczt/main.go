@@ -0,0 +1,206 @@
+// Command pczt is a reference CLI for the PCZT role-separated workflow: each
+// subcommand acts as exactly one Role (see t2z.Role) and goes through the
+// matching RoleAs-suffixed function, so the capability boundary those
+// functions enforce is visible on the command line too, not just in library
+// code.
+//
+// Usage:
+//
+//	pczt inspect <pczt-file>
+//	pczt sign --key <hex-privkey> --inputs <inputs.json> <in-file> <out-file>
+//	pczt combine <out-file> <in-file>...
+//	pczt finalize <pczt-file> <tx-out-file>
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	t2z "github.com/gstohl/t2z/go"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "inspect":
+		err = runInspect(os.Args[2:])
+	case "sign":
+		err = runSign(os.Args[2:])
+	case "combine":
+		err = runCombine(os.Args[2:])
+	case "finalize":
+		err = runFinalize(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "pczt:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: pczt inspect|sign|combine|finalize ...")
+	os.Exit(2)
+}
+
+// runInspect loads a PCZT (as the Updater would, to look it over without
+// claiming any role that can mutate it) and prints its role and summary.
+func runInspect(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: pczt inspect <pczt-file>")
+	}
+	pczt, role, err := t2z.LoadFromFile(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("produced by: %s\n", role)
+	summary, err := t2z.SummarizePCZT(pczt)
+	if err != nil {
+		return err
+	}
+	fmt.Print(summary)
+	return nil
+}
+
+// inputSpec is the JSON shape --inputs expects: the same fields as
+// t2z.TransparentInput, hex-encoded for the byte fields.
+type inputSpec struct {
+	Pubkey       string `json:"pubkey"`
+	TxID         string `json:"txid"`
+	Vout         uint32 `json:"vout"`
+	Amount       uint64 `json:"amount"`
+	ScriptPubKey string `json:"script_pubkey"`
+}
+
+func loadInputs(path string) ([]t2z.TransparentInput, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var specs []inputSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	inputs := make([]t2z.TransparentInput, len(specs))
+	for i, s := range specs {
+		pubkey, err := hex.DecodeString(s.Pubkey)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: decoding pubkey: %w", i, err)
+		}
+		txidBytes, err := hex.DecodeString(s.TxID)
+		if err != nil || len(txidBytes) != 32 {
+			return nil, fmt.Errorf("input %d: decoding txid: %w", i, err)
+		}
+		scriptPubKey, err := hex.DecodeString(s.ScriptPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: decoding script_pubkey: %w", i, err)
+		}
+		var txid [32]byte
+		copy(txid[:], txidBytes)
+		inputs[i] = t2z.TransparentInput{Pubkey: pubkey, TxID: txid, Vout: s.Vout, Amount: s.Amount, ScriptPubKey: scriptPubKey}
+	}
+	return inputs, nil
+}
+
+// runSign acts as the Signer: it appends a signature for every input the
+// given key matches, refusing (via AppendSignatureAs) if the loaded PCZT
+// claims to have been produced by a role that isn't expecting a Signer next.
+func runSign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	keyHex := fs.String("key", "", "hex-encoded secp256k1 private key")
+	inputsPath := fs.String("inputs", "", "path to a JSON file describing this PCZT's TransparentInputs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 || *keyHex == "" || *inputsPath == "" {
+		return fmt.Errorf("usage: pczt sign --key <hex-privkey> --inputs <inputs.json> <in-file> <out-file>")
+	}
+	inFile, outFile := fs.Arg(0), fs.Arg(1)
+
+	keyBytes, err := hex.DecodeString(*keyHex)
+	if err != nil {
+		return fmt.Errorf("decoding --key: %w", err)
+	}
+	key := secp256k1.PrivKeyFromBytes(keyBytes)
+	signer := t2z.NewLocalSecpSigner(key)
+
+	inputs, err := loadInputs(*inputsPath)
+	if err != nil {
+		return err
+	}
+
+	pczt, _, err := t2z.LoadFromFile(inFile)
+	if err != nil {
+		return err
+	}
+
+	current := pczt
+	for i := range inputs {
+		sighash, err := t2z.GetSighash(current, uint(i))
+		if err != nil {
+			return fmt.Errorf("getting sighash for input %d: %w", i, err)
+		}
+		sig, err := signer.Sign(i, sighash, inputs[i].Pubkey)
+		if err != nil {
+			continue // this key doesn't match this input; leave it for another signer
+		}
+		current, err = t2z.AppendSignatureAs(t2z.RoleSigner, current, uint(i), sig)
+		if err != nil {
+			return fmt.Errorf("appending signature for input %d: %w", i, err)
+		}
+	}
+
+	return t2z.SaveToFile(outFile, current, t2z.RoleSigner)
+}
+
+// runCombine acts as the Combiner, merging every signed copy in files into
+// one PCZT carrying every signature any of them supplied.
+func runCombine(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: pczt combine <out-file> <in-file>...")
+	}
+	outFile, inFiles := args[0], args[1:]
+
+	pczts := make([]*t2z.PCZT, len(inFiles))
+	for i, f := range inFiles {
+		pczt, _, err := t2z.LoadFromFile(f)
+		if err != nil {
+			return err
+		}
+		pczts[i] = pczt
+	}
+
+	combined, err := t2z.CombineAs(t2z.RoleCombiner, pczts)
+	if err != nil {
+		return err
+	}
+	return t2z.SaveToFile(outFile, combined, t2z.RoleCombiner)
+}
+
+// runFinalize acts as the Finalizer, assembling every input's scriptSig and
+// writing the resulting raw transaction bytes (hex-encoded) to txOutFile.
+func runFinalize(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: pczt finalize <pczt-file> <tx-out-file>")
+	}
+	pcztFile, txOutFile := args[0], args[1]
+
+	pczt, _, err := t2z.LoadFromFile(pcztFile)
+	if err != nil {
+		return err
+	}
+	txBytes, err := t2z.FinalizeAndExtractAs(t2z.RoleFinalizer, pczt)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(txOutFile, []byte(hex.EncodeToString(txBytes)), 0o600)
+}
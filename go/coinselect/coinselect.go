@@ -0,0 +1,168 @@
+// Package coinselect turns a wallet's available transparent UTXOs into the
+// []t2z.TransparentInput slice t2z.ProposeTransaction expects, so callers no
+// longer have to hand-pick inputs and hard-code a fee. It builds on the
+// InputSource strategies in the root t2z package (UTXOPool, BranchAndBoundSource)
+// rather than reimplementing selection itself.
+package coinselect
+
+import (
+	"fmt"
+
+	t2z "github.com/gstohl/t2z/go"
+)
+
+// Strategy selects which coin-selection algorithm UTXOSet.Select uses.
+type Strategy int
+
+const (
+	// LargestFirst selects UTXOs largest-first, minimizing the number of
+	// transparent inputs (and therefore the ZIP-317 marginal fee).
+	LargestFirst Strategy = iota
+	// BranchAndBound searches for a changeless subset within a fee tolerance
+	// window before falling back to LargestFirst, the Bitcoin-Core-style
+	// "avoid a change output when possible" strategy.
+	BranchAndBound
+)
+
+// defaultDustThreshold is the residual below which Select absorbs leftover
+// value into the fee instead of creating a change output.
+const defaultDustThreshold = 5_000
+
+// UTXO describes one available transparent output a wallet could spend,
+// including the derivation path needed to sign for it later (e.g. with
+// t2z/hdwallet).
+type UTXO struct {
+	TxID         [32]byte
+	Vout         uint32
+	Amount       uint64
+	ScriptPubKey []byte
+	Pubkey       []byte
+	Path         string
+}
+
+// SelectParams configures a UTXOSet.Select call.
+type SelectParams struct {
+	Strategy Strategy
+	// MaxInputs caps how many UTXOs Select may select, 0 meaning no cap.
+	MaxInputs int
+	// DustThreshold is the residual below which Select folds change into the
+	// fee rather than creating a change output. Zero means defaultDustThreshold.
+	DustThreshold uint64
+	// HasUnifiedRecipient should be true if any payment in the Select call
+	// targets a unified address, adding one Orchard action to the ZIP-317 fee
+	// calculation for the output side.
+	HasUnifiedRecipient bool
+}
+
+// Selection is the result of a successful UTXOSet.Select call.
+type Selection struct {
+	Inputs []t2z.TransparentInput
+	// Paths holds the derivation path for each entry of Inputs, in the same
+	// order, for callers that sign with t2z/hdwallet.
+	Paths  []string
+	Fee    uint64
+	Change uint64
+}
+
+// UTXOSet holds the UTXOs a wallet can spend from.
+type UTXOSet struct {
+	utxos []UTXO
+}
+
+// NewUTXOSet builds a UTXOSet over utxos, which need not be sorted.
+func NewUTXOSet(utxos []UTXO) *UTXOSet {
+	return &UTXOSet{utxos: append([]UTXO{}, utxos...)}
+}
+
+// Select picks inputs from s covering payments plus a ZIP-317-computed fee,
+// according to params.Strategy, and returns the inputs (paired with their
+// derivation paths), the fee charged, and any change left over.
+func (s *UTXOSet) Select(payments []t2z.Payment, params SelectParams) (*Selection, error) {
+	dustThreshold := params.DustThreshold
+	if dustThreshold == 0 {
+		dustThreshold = defaultDustThreshold
+	}
+
+	paymentTotal := uint64(0)
+	for _, p := range payments {
+		paymentTotal += p.Amount
+	}
+
+	orchardActions := 0
+	if params.HasUnifiedRecipient {
+		orchardActions = 1
+	}
+
+	pathByOutpoint := make(map[string]string, len(s.utxos))
+	all := make([]t2z.TransparentInput, 0, len(s.utxos))
+	for _, u := range s.utxos {
+		all = append(all, t2z.TransparentInput{TxID: u.TxID, Vout: u.Vout, Amount: u.Amount, ScriptPubKey: u.ScriptPubKey, Pubkey: u.Pubkey})
+		pathByOutpoint[outpointKey(u.TxID, u.Vout)] = u.Path
+	}
+
+	source, err := newSource(params.Strategy, all)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		selected  []t2z.TransparentInput
+		total     uint64
+		fee       uint64
+		hasChange bool
+	)
+	for {
+		outputs := len(payments)
+		if hasChange {
+			outputs++
+		}
+		fee = t2z.CalculateFee(len(selected)+1, outputs, orchardActions)
+		target := paymentTotal + fee
+
+		if total >= target {
+			break
+		}
+
+		newSelected, newTotal, err := source.SelectInputs(target)
+		if err != nil {
+			return nil, fmt.Errorf("coinselect: selecting inputs: %w", err)
+		}
+		if params.MaxInputs > 0 && len(newSelected) > params.MaxInputs {
+			return nil, fmt.Errorf("coinselect: covering %d zatoshis needs more than the %d-input cap", target, params.MaxInputs)
+		}
+		if newTotal < target && newTotal <= total {
+			return nil, &t2z.InsufficientFundsError{Needed: target, Available: newTotal}
+		}
+		selected, total = newSelected, newTotal
+		hasChange = total-paymentTotal > fee+dustThreshold
+	}
+
+	paths := make([]string, len(selected))
+	for i, input := range selected {
+		paths[i] = pathByOutpoint[outpointKey(input.TxID, input.Vout)]
+	}
+
+	change := total - paymentTotal - fee
+	if change <= dustThreshold {
+		change = 0
+	}
+
+	return &Selection{Inputs: selected, Paths: paths, Fee: fee, Change: change}, nil
+}
+
+// newSource builds the t2z.InputSource matching strategy.
+func newSource(strategy Strategy, utxos []t2z.TransparentInput) (t2z.InputSource, error) {
+	switch strategy {
+	case LargestFirst:
+		return t2z.NewUTXOPool(utxos), nil
+	case BranchAndBound:
+		return t2z.NewBranchAndBoundSource(utxos), nil
+	default:
+		return nil, fmt.Errorf("coinselect: unknown strategy %d", strategy)
+	}
+}
+
+// outpointKey is a comparable map key for a UTXO's outpoint.
+func outpointKey(txid [32]byte, vout uint32) string {
+	return fmt.Sprintf("%x:%d", txid, vout)
+}
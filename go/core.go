@@ -0,0 +1,577 @@
+package t2z
+
+import (
+	"fmt"
+
+	"github.com/gstohl/t2z/go/address"
+)
+
+// This file is the pure-Go transparent-only PCZT builder the rest of this
+// package assumes: Payment/TransparentInput/TransparentOutput as the data
+// model, and NewTransactionRequest/ProposeTransaction/ProveTransaction/
+// GetSighash/AppendSignature/FinalizeAndExtract/CalculateFee/Serialize/Parse
+// as the lifecycle every other file in this module builds on. It produces
+// and consumes real ZIP-225 v5 transparent bundles via parseV5Transaction/
+// zip244TransparentSighash, the same machinery VerifyTransaction and
+// VerifyExtractedTx already use to check the other side of this round trip.
+//
+// It only ever builds transparent outputs: a Payment to a Sapling or unified
+// address fails with ErrShieldedOutputsUnsupported rather than silently
+// dropping the payment, the same way ErrUnifiedReceiversUnsupported and
+// ErrWitnessStyleUnsupported disclose gaps elsewhere in this package.
+
+// v5VersionGroupID and v5ConsensusBranchID are the NU5/ZIP-225 constants
+// every transaction this package builds is tagged with.
+const (
+	v5VersionGroupID    = 0x26a7270a
+	v5ConsensusBranchID = 0xc2d6da25
+	v5ExpiryDelta       = 20
+)
+
+// ErrShieldedOutputsUnsupported is returned by NewTransactionRequest and
+// ProposeTransaction for a Payment whose Address is not a transparent
+// address: building a real Sapling or Orchard output requires proving keys
+// this pure-Go package does not have.
+var ErrShieldedOutputsUnsupported = fmt.Errorf("t2z: only transparent payments are supported by this PCZT builder; shielded/unified outputs require the librustzcash-backed core")
+
+// Payment is one destination of a TransactionRequest: an amount paid to
+// Address, with an optional Memo (meaningful only for a shielded output,
+// which this package does not yet build - see ErrShieldedOutputsUnsupported).
+type Payment struct {
+	Address string
+	Amount  uint64
+	Memo    string
+
+	// isChange marks a payment ProposeTransactionWithSources appended as a
+	// change output rather than one the caller requested, so SummarizePCZT
+	// can flag it as such instead of showing it as an ordinary payment.
+	isChange bool
+}
+
+// TransparentInput funds a TransactionRequest. A plain P2PKH spend only needs
+// Pubkey/TxID/Vout/Amount/ScriptPubKey; RedeemScript and Multisig are set
+// together by NewMultisigInput for a P2SH m-of-n CHECKMULTISIG spend.
+type TransparentInput struct {
+	Pubkey       []byte
+	TxID         [32]byte
+	Vout         uint32
+	Amount       uint64
+	ScriptPubKey []byte
+	RedeemScript []byte
+	Multisig     *MultisigConfig
+}
+
+// TransparentOutput is a previous transparent output being spent, as needed
+// by VerifyTransaction/VerifyBeforeSigning to recompute a sighash without a
+// full TransparentInput.
+type TransparentOutput struct {
+	ScriptPubKey []byte
+	Amount       uint64
+}
+
+// TransactionRequest collects the payments a proposed transaction should
+// make. Free releases any resources the underlying builder holds; on this
+// pure-Go implementation there is nothing to release, but every caller up
+// the stack defers it, so the method stays cheap rather than a no-op they
+// have to special-case out.
+type TransactionRequest struct {
+	Payments []Payment
+
+	useMainnet   bool
+	targetHeight uint32
+}
+
+// NewTransactionRequest builds a TransactionRequest for payments, which must
+// be non-empty.
+func NewTransactionRequest(payments []Payment) (*TransactionRequest, error) {
+	if len(payments) == 0 {
+		return nil, fmt.Errorf("t2z: transaction request needs at least one payment")
+	}
+	return &TransactionRequest{
+		Payments:   append([]Payment{}, payments...),
+		useMainnet: false,
+	}, nil
+}
+
+// Free releases request's resources. On this implementation it is a no-op.
+func (r *TransactionRequest) Free() {}
+
+// SetUseMainnet selects which network addresses in r.Payments are validated
+// against; testnet by default.
+func (r *TransactionRequest) SetUseMainnet(useMainnet bool) { r.useMainnet = useMainnet }
+
+// SetTargetHeight sets the block height the proposed transaction targets,
+// used to derive its expiry height.
+func (r *TransactionRequest) SetTargetHeight(height uint32) { r.targetHeight = height }
+
+// pcztInput is one TransparentInput plus the signature (if any) appended for
+// it so far.
+type pcztInput struct {
+	TransparentInput
+	sig         *[64]byte
+	sighashType SighashType
+}
+
+// pcztOutput is one resolved transparent output a PCZT will produce.
+type pcztOutput struct {
+	scriptPubKey []byte
+	amount       uint64
+}
+
+// PCZT is a partially created Zcash transaction: the transparent inputs and
+// outputs ProposeTransaction resolved from a TransactionRequest, plus
+// whatever signatures have been appended so far. Every operation on a PCZT
+// (ProveTransaction, AppendSignature, ...) returns a new *PCZT rather than
+// mutating in place, mirroring the consume-and-replace handle semantics a
+// real FFI-backed builder would have; callers should stop using a PCZT once
+// they've passed it to the next step, as the examples throughout this
+// package do.
+type PCZT struct {
+	inputs       []pcztInput
+	outputs      []pcztOutput
+	payments     []Payment
+	useMainnet   bool
+	targetHeight uint32
+	proved       bool
+}
+
+// Free releases pczt's resources. On this implementation it is a no-op.
+func (pczt *PCZT) Free() {}
+
+// clone returns a shallow copy of pczt with its own inputs slice, so callers
+// can produce a new handle without mutating the one they were given.
+func (pczt *PCZT) clone() *PCZT {
+	c := *pczt
+	c.inputs = append([]pcztInput{}, pczt.inputs...)
+	c.outputs = append([]pcztOutput{}, pczt.outputs...)
+	return &c
+}
+
+// resolveOutput builds the scriptPubKey for payment, failing with
+// ErrShieldedOutputsUnsupported if it isn't a transparent address, or if it
+// is for the wrong network.
+func resolveOutput(payment Payment, useMainnet bool) (pcztOutput, error) {
+	addr, err := address.Parse(payment.Address)
+	if err != nil {
+		return pcztOutput{}, fmt.Errorf("%w: %q: %v", ErrShieldedOutputsUnsupported, payment.Address, err)
+	}
+	if addr.Mainnet != useMainnet {
+		return pcztOutput{}, fmt.Errorf("t2z: address %q is not on the requested network", payment.Address)
+	}
+	return pcztOutput{scriptPubKey: addr.ScriptPubKey(), amount: payment.Amount}, nil
+}
+
+// ProposeTransaction builds a PCZT spending inputs to satisfy request: one
+// transparent output per request.Payments entry, in order.
+func ProposeTransaction(inputs []TransparentInput, request *TransactionRequest) (*PCZT, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("t2z: proposing transaction: no inputs")
+	}
+
+	var inTotal uint64
+	pInputs := make([]pcztInput, len(inputs))
+	for i, in := range inputs {
+		pInputs[i] = pcztInput{TransparentInput: in, sighashType: SighashAll}
+		inTotal += in.Amount
+	}
+
+	outputs := make([]pcztOutput, len(request.Payments))
+	var outTotal uint64
+	for i, p := range request.Payments {
+		out, err := resolveOutput(p, request.useMainnet)
+		if err != nil {
+			return nil, fmt.Errorf("t2z: proposing transaction: payment %d: %w", i, err)
+		}
+		outputs[i] = out
+		outTotal += p.Amount
+	}
+
+	if outTotal > inTotal {
+		return nil, fmt.Errorf("t2z: proposing transaction: payments total %d exceeds input total %d", outTotal, inTotal)
+	}
+
+	return &PCZT{
+		inputs:       pInputs,
+		outputs:      outputs,
+		payments:     append([]Payment{}, request.Payments...),
+		useMainnet:   request.useMainnet,
+		targetHeight: request.targetHeight,
+	}, nil
+}
+
+// AddOutput appends payment as an additional transparent output of pczt,
+// returning a new PCZT with it resolved and added. This is the Updater's
+// operation in the PCZT role model (see AddOutputAs): adding data to a
+// proposal the Creator already built, before any Signer has signed it.
+func AddOutput(pczt *PCZT, payment Payment) (*PCZT, error) {
+	out, err := resolveOutput(payment, pczt.useMainnet)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: adding output: %w", err)
+	}
+	c := pczt.clone()
+	c.outputs = append(c.outputs, out)
+	c.payments = append(c.payments, payment)
+	return c, nil
+}
+
+// ProveTransaction marks pczt ready for signing. A transparent-only PCZT has
+// no Sapling/Orchard proofs to generate, so this is otherwise a no-op; it
+// exists so code written against a hybrid transparent/shielded builder -
+// where this step does real work - doesn't need a transparent-only special
+// case.
+func ProveTransaction(pczt *PCZT) (*PCZT, error) {
+	c := pczt.clone()
+	c.proved = true
+	return c, nil
+}
+
+// toParsedTx builds the parsedTransaction pczt currently describes, with
+// every input's scriptSig filled in from its appended signature (nil for an
+// input that isn't signed yet, or that relies on an external finalization
+// step like FinalizeMultisigAndExtract).
+func (pczt *PCZT) toParsedTx() *parsedTransaction {
+	tx := &parsedTransaction{
+		Version:         5,
+		VersionGroupID:  v5VersionGroupID,
+		ConsensusHeight: v5ConsensusBranchID,
+		ExpiryHeight:    pczt.targetHeight + v5ExpiryDelta,
+	}
+	for _, in := range pczt.inputs {
+		tx.Inputs = append(tx.Inputs, parsedTransparentInput{
+			PrevTxID:  in.TxID,
+			PrevVout:  in.Vout,
+			ScriptSig: in.scriptSig(),
+			Sequence:  0xffffffff,
+		})
+	}
+	for _, out := range pczt.outputs {
+		tx.Outputs = append(tx.Outputs, parsedTransparentOutput{Amount: out.amount, ScriptPubKey: out.scriptPubKey})
+	}
+	return tx
+}
+
+// scriptSig assembles in's final scriptSig from its appended signature: the
+// standard P2PKH `<sig><sighashtype> <pubkey>` push pair. An input with no
+// signature yet, or one with a Multisig config (finalized separately by
+// FinalizeMultisigAndExtract), produces no scriptSig.
+func (in *pcztInput) scriptSig() []byte {
+	if in.sig == nil {
+		return nil
+	}
+	sig := append(derEncodeSignature(*in.sig), byte(in.sighashType))
+	return append(pushData(sig), pushData(in.Pubkey)...)
+}
+
+// sighashScriptCode returns the scriptCode GetSighash/GetSighashWithType must
+// hash for in: the redeem script for a P2SH multisig input (what
+// verifyP2SHMultisigInput actually checks signatures against), or
+// ScriptPubKey for a plain P2PKH input.
+func sighashScriptCode(in TransparentInput) []byte {
+	if in.RedeemScript != nil {
+		return in.RedeemScript
+	}
+	return in.ScriptPubKey
+}
+
+// GetSighash returns the ZIP-244 signature hash input inputIndex needs to be
+// signed against, implicitly under SIGHASH_ALL (see GetSighashWithType for
+// other types). For a multisig input it binds to the redeem script, not the
+// P2SH ScriptPubKey, matching what verifyP2SHMultisigInput checks signatures
+// against.
+func GetSighash(pczt *PCZT, inputIndex uint) ([32]byte, error) {
+	if inputIndex >= uint(len(pczt.inputs)) {
+		return [32]byte{}, fmt.Errorf("t2z: input index %d out of range (have %d inputs)", inputIndex, len(pczt.inputs))
+	}
+	tx := pczt.toParsedTx()
+	in := pczt.inputs[inputIndex]
+	return zip244TransparentSighash(tx, int(inputIndex), sighashScriptCode(in.TransparentInput), in.Amount), nil
+}
+
+// AppendSignature appends sig (a compact r||s ECDSA signature, implicitly
+// SIGHASH_ALL) for inputIndex, returning a new PCZT with it recorded.
+func AppendSignature(pczt *PCZT, inputIndex uint, sig [64]byte) (*PCZT, error) {
+	if inputIndex >= uint(len(pczt.inputs)) {
+		return nil, fmt.Errorf("t2z: input index %d out of range (have %d inputs)", inputIndex, len(pczt.inputs))
+	}
+	c := pczt.clone()
+	c.inputs[inputIndex].sig = &sig
+	c.inputs[inputIndex].sighashType = SighashAll
+	return c, nil
+}
+
+// FinalizeAndExtract assembles every signed input's scriptSig and returns the
+// finalized transaction's raw bytes. An input with a Multisig config may be
+// left unsigned here; FinalizeMultisigAndExtract patches its scriptSig in
+// afterward. Any other input must already have a signature appended.
+func FinalizeAndExtract(pczt *PCZT) ([]byte, error) {
+	for i, in := range pczt.inputs {
+		if in.sig == nil && in.Multisig == nil {
+			return nil, fmt.Errorf("t2z: finalizing: input %d has no signature", i)
+		}
+	}
+	return pczt.toParsedTx().serialize(), nil
+}
+
+// CalculateFee computes the ZIP-317 conventional fee for a transaction with
+// numTransparentIn transparent inputs, numTransparentOut transparent
+// outputs, and numShielded Sapling/Orchard actions: the marginal fee per
+// logical action beyond the grace allowance, where the logical action count
+// is the larger of the transparent input/output counts plus the shielded
+// action count.
+func CalculateFee(numTransparentIn, numTransparentOut, numShielded int) uint64 {
+	logicalActions := numTransparentIn
+	if numTransparentOut > logicalActions {
+		logicalActions = numTransparentOut
+	}
+	logicalActions += numShielded
+	if logicalActions < zip317GraceActions {
+		logicalActions = zip317GraceActions
+	}
+	return uint64(logicalActions) * zip317MarginalFee
+}
+
+// ErrOutputMismatch is returned by VerifyBeforeSigning when pczt's resolved
+// outputs don't match request's payments, e.g. because pczt was built from a
+// different (possibly malicious) request than the one about to be signed
+// against.
+var ErrOutputMismatch = fmt.Errorf("t2z: PCZT outputs do not match the transaction request; refusing to sign")
+
+// VerifyBeforeSigning checks that pczt actually matches request and
+// prevOutputs before a caller signs it - the local check a wallet should run
+// in place of trusting that whoever called ProposeTransaction built the PCZT
+// it claims to have. It verifies every payment in request appears, in order
+// and for the same amount, among pczt's resolved outputs (rejecting an
+// injected or altered payment), and, when prevOutputs is non-empty, that it
+// has the same length as pczt's inputs.
+func VerifyBeforeSigning(pczt *PCZT, request *TransactionRequest, prevOutputs []TransparentOutput) error {
+	if len(prevOutputs) > 0 && len(prevOutputs) != len(pczt.inputs) {
+		return fmt.Errorf("t2z: have %d transparent inputs but %d previous outputs", len(pczt.inputs), len(prevOutputs))
+	}
+
+	if len(request.Payments) > len(pczt.outputs) {
+		return ErrOutputMismatch
+	}
+	for i, p := range request.Payments {
+		out, err := resolveOutput(p, pczt.useMainnet)
+		if err != nil {
+			return fmt.Errorf("t2z: verifying payment %d: %w", i, err)
+		}
+		got := pczt.outputs[i]
+		if got.amount != out.amount || string(got.scriptPubKey) != string(out.scriptPubKey) {
+			return ErrOutputMismatch
+		}
+	}
+	return nil
+}
+
+// Combine merges a set of PCZTs that were each independently signed from
+// copies of the same proposal - one per co-signer - into a single PCZT
+// carrying every signature that was appended to any of them. It rejects
+// pczts whose inputs/outputs don't all describe the same proposed
+// transaction, and errors if two copies supply conflicting signatures for
+// the same input.
+func Combine(pczts []*PCZT) (*PCZT, error) {
+	if len(pczts) == 0 {
+		return nil, fmt.Errorf("t2z: combining PCZTs: need at least one")
+	}
+	combined := pczts[0].clone()
+	for _, p := range pczts[1:] {
+		if len(p.inputs) != len(combined.inputs) || len(p.outputs) != len(combined.outputs) {
+			return nil, fmt.Errorf("t2z: combining PCZTs: inputs/outputs do not match the same proposal")
+		}
+		for i, in := range p.inputs {
+			if in.TxID != combined.inputs[i].TxID || in.Vout != combined.inputs[i].Vout {
+				return nil, fmt.Errorf("t2z: combining PCZTs: input %d does not match the same proposal", i)
+			}
+			if in.sig == nil {
+				continue
+			}
+			if combined.inputs[i].sig != nil && *combined.inputs[i].sig != *in.sig {
+				return nil, fmt.Errorf("t2z: combining PCZTs: input %d has conflicting signatures", i)
+			}
+			combined.inputs[i].sig = in.sig
+			combined.inputs[i].sighashType = in.sighashType
+		}
+	}
+	return combined, nil
+}
+
+// Serialize encodes pczt into a self-contained byte representation that
+// Parse can read back, without consuming pczt.
+func Serialize(pczt *PCZT) ([]byte, error) {
+	var out []byte
+	out = append(out, encodeCompactSize(uint64(pczt.targetHeight))...)
+	if pczt.useMainnet {
+		out = appendVarBytes(out, []byte{1})
+	} else {
+		out = appendVarBytes(out, []byte{0})
+	}
+	if pczt.proved {
+		out = appendVarBytes(out, []byte{1})
+	} else {
+		out = appendVarBytes(out, []byte{0})
+	}
+
+	out = append(out, encodeCompactSize(uint64(len(pczt.inputs)))...)
+	for _, in := range pczt.inputs {
+		out = append(out, in.TxID[:]...)
+		out = append(out, le32(in.Vout)...)
+		out = append(out, le64(in.Amount)...)
+		out = appendVarBytes(out, in.Pubkey)
+		out = appendVarBytes(out, in.ScriptPubKey)
+		out = appendVarBytes(out, in.RedeemScript)
+		if in.sig != nil {
+			out = appendVarBytes(out, []byte{1})
+			out = appendVarBytes(out, []byte{byte(in.sighashType)})
+			out = append(out, in.sig[:]...)
+		} else {
+			out = appendVarBytes(out, []byte{0})
+		}
+	}
+
+	out = append(out, encodeCompactSize(uint64(len(pczt.outputs)))...)
+	for _, o := range pczt.outputs {
+		out = append(out, le64(o.amount)...)
+		out = appendVarBytes(out, o.scriptPubKey)
+	}
+
+	out = append(out, encodeCompactSize(uint64(len(pczt.payments)))...)
+	for _, p := range pczt.payments {
+		out = appendVarBytes(out, []byte(p.Address))
+		out = append(out, le64(p.Amount)...)
+		out = appendVarBytes(out, []byte(p.Memo))
+	}
+
+	return out, nil
+}
+
+// Parse reverses Serialize.
+func Parse(data []byte) (*PCZT, error) {
+	r := &byteReader{buf: data}
+
+	targetHeight, err := r.readCompactSize()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: parsing PCZT: reading target height: %w", err)
+	}
+	useMainnetByte, err := r.readVarBytes1()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: parsing PCZT: reading network flag: %w", err)
+	}
+	provedByte, err := r.readVarBytes1()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: parsing PCZT: reading proved flag: %w", err)
+	}
+
+	pczt := &PCZT{
+		targetHeight: uint32(targetHeight),
+		useMainnet:   useMainnetByte == 1,
+		proved:       provedByte == 1,
+	}
+
+	numInputs, err := r.readCompactSize()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: parsing PCZT: reading input count: %w", err)
+	}
+	for i := uint64(0); i < numInputs; i++ {
+		var in pcztInput
+		if err := r.readBytesInto(in.TxID[:]); err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: input %d: reading txid: %w", i, err)
+		}
+		if in.Vout, err = r.readUint32LE(); err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: input %d: reading vout: %w", i, err)
+		}
+		if in.Amount, err = r.readUint64LE(); err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: input %d: reading amount: %w", i, err)
+		}
+		if in.Pubkey, err = r.readVarBytes(); err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: input %d: reading pubkey: %w", i, err)
+		}
+		if in.ScriptPubKey, err = r.readVarBytes(); err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: input %d: reading scriptPubKey: %w", i, err)
+		}
+		if in.RedeemScript, err = r.readVarBytes(); err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: input %d: reading redeem script: %w", i, err)
+		}
+		hasSig, err := r.readVarBytes1()
+		if err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: input %d: reading signature flag: %w", i, err)
+		}
+		if hasSig == 1 {
+			sighashType, err := r.readVarBytes1()
+			if err != nil {
+				return nil, fmt.Errorf("t2z: parsing PCZT: input %d: reading sighash type: %w", i, err)
+			}
+			in.sighashType = SighashType(sighashType)
+			var sig [64]byte
+			if err := r.readBytesInto(sig[:]); err != nil {
+				return nil, fmt.Errorf("t2z: parsing PCZT: input %d: reading signature: %w", i, err)
+			}
+			in.sig = &sig
+		}
+		pczt.inputs = append(pczt.inputs, in)
+	}
+
+	numOutputs, err := r.readCompactSize()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: parsing PCZT: reading output count: %w", err)
+	}
+	for i := uint64(0); i < numOutputs; i++ {
+		var out pcztOutput
+		if out.amount, err = r.readUint64LE(); err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: output %d: reading amount: %w", i, err)
+		}
+		if out.scriptPubKey, err = r.readVarBytes(); err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: output %d: reading scriptPubKey: %w", i, err)
+		}
+		pczt.outputs = append(pczt.outputs, out)
+	}
+
+	numPayments, err := r.readCompactSize()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: parsing PCZT: reading payment count: %w", err)
+	}
+	for i := uint64(0); i < numPayments; i++ {
+		var p Payment
+		address, err := r.readVarBytes()
+		if err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: payment %d: reading address: %w", i, err)
+		}
+		p.Address = string(address)
+		if p.Amount, err = r.readUint64LE(); err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: payment %d: reading amount: %w", i, err)
+		}
+		memo, err := r.readVarBytes()
+		if err != nil {
+			return nil, fmt.Errorf("t2z: parsing PCZT: payment %d: reading memo: %w", i, err)
+		}
+		p.Memo = string(memo)
+		pczt.payments = append(pczt.payments, p)
+	}
+
+	return pczt, nil
+}
+
+// SerializePCZT and ParsePCZT are Serialize and Parse under the names used by
+// the examples under bindings/go, which predate the Serialize/Parse rename.
+func SerializePCZT(pczt *PCZT) ([]byte, error) { return Serialize(pczt) }
+func ParsePCZT(data []byte) (*PCZT, error)     { return Parse(data) }
+
+// appendVarBytes appends data to out as a CompactSize length prefix followed
+// by data itself, the length-prefixed encoding Serialize uses throughout.
+func appendVarBytes(out, data []byte) []byte {
+	out = append(out, encodeCompactSize(uint64(len(data)))...)
+	return append(out, data...)
+}
+
+// readVarBytes1 reads a single length-prefixed byte (Serialize's encoding
+// for a one-byte flag), returning that byte.
+func (r *byteReader) readVarBytes1() (byte, error) {
+	b, err := r.readVarBytes()
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 1 {
+		return 0, fmt.Errorf("expected a single byte, got %d", len(b))
+	}
+	return b[0], nil
+}
@@ -10,9 +10,9 @@ import (
 	"fmt"
 	"log"
 
-	t2z "github.com/gstohl/t2z/go"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	t2z "github.com/gstohl/t2z/go"
 )
 
 func main() {
@@ -32,7 +32,7 @@ func main() {
 		{
 			Address: "tm9iMLAuYMzJ6jtFLcA7rzUmfreGuKvr7Ma",
 			Amount:  50_000, // 0.0005 ZEC
-			Label:   "Hardware wallet test",
+			Memo:    "Hardware wallet test",
 		},
 	}
 
@@ -125,37 +125,37 @@ func main() {
 		fmt.Println("   ✓ Verification passed")
 	}
 
-	// Get sighash for the input
-	fmt.Println("🔐 HARDWARE WALLET: Computing sighash...")
-	sighash, err := t2z.GetSighash(hwPczt, 0)
-	if err != nil {
-		log.Fatalf("Failed to get sighash: %v", err)
-	}
-	fmt.Printf("   ✓ Sighash: %s\n", hex.EncodeToString(sighash[:16]))
-
-	// Display sighash to user for verification (optional)
-	fmt.Println("🔐 HARDWARE WALLET: [Display on screen]")
-	fmt.Println("   ┌─────────────────────────────────┐")
-	fmt.Println("   │ Sign Transaction?               │")
-	fmt.Printf("   │ Sighash: %s... │\n", hex.EncodeToString(sighash[:8]))
-	fmt.Println("   │ [Confirm] [Reject]              │")
-	fmt.Println("   └─────────────────────────────────┘")
-
-	// User confirms on hardware wallet
-	fmt.Println("👤 USER: [Presses CONFIRM button]\n")
-
-	// Hardware wallet signs with secure key (simulated here)
-	fmt.Println("🔐 HARDWARE WALLET: Signing with secure key...")
-	compactSig := ecdsa.SignCompact(privKey, sighash[:], true)
-	var signature [64]byte
-	copy(signature[:], compactSig[1:])
-	fmt.Printf("   ✓ Signature: %s...\n", hex.EncodeToString(signature[:16]))
-
-	// Append signature to PCZT
+	// Sign every input in one call: SignAll drives the GetSighash/sign/
+	// AppendSignature loop itself, calling back into hwSigner per input
+	// instead of making the coordinator hand-roll it.
+	fmt.Println("🔐 HARDWARE WALLET: Computing sighash and signing...")
+	hwSigner := t2z.NewCallbackSigner(func(inputIndex int, sighash [32]byte, pubkey []byte) ([64]byte, error) {
+		fmt.Printf("   ✓ Sighash: %s\n", hex.EncodeToString(sighash[:16]))
+
+		// Display sighash to user for verification (optional)
+		fmt.Println("🔐 HARDWARE WALLET: [Display on screen]")
+		fmt.Println("   ┌─────────────────────────────────┐")
+		fmt.Println("   │ Sign Transaction?               │")
+		fmt.Printf("   │ Sighash: %s... │\n", hex.EncodeToString(sighash[:8]))
+		fmt.Println("   │ [Confirm] [Reject]              │")
+		fmt.Println("   └─────────────────────────────────┘")
+
+		// User confirms on hardware wallet
+		fmt.Println("👤 USER: [Presses CONFIRM button]\n")
+
+		// Hardware wallet signs with secure key (simulated here)
+		fmt.Println("🔐 HARDWARE WALLET: Signing with secure key...")
+		compactSig := ecdsa.SignCompact(privKey, sighash[:], true)
+		var signature [64]byte
+		copy(signature[:], compactSig[1:])
+		fmt.Printf("   ✓ Signature: %s...\n", hex.EncodeToString(signature[:16]))
+		return signature, nil
+	})
+
 	fmt.Println("🔐 HARDWARE WALLET: Adding signature to PCZT...")
-	signedPczt, err := t2z.AppendSignature(hwPczt, 0, signature)
+	signedPczt, err := t2z.SignAll(hwPczt, inputs, hwSigner)
 	if err != nil {
-		log.Fatalf("Failed to append signature: %v", err)
+		log.Fatalf("Failed to sign: %v", err)
 	}
 	fmt.Println("   ✓ Signature appended")
 
@@ -136,12 +136,8 @@ func main() {
 	}
 	defer request.Free()
 
-	// Mainnet is the default, just set target height
-	err = request.SetTargetHeight(2_500_000)
-	if err != nil {
-		log.Fatalf("Failed to set target height: %v", err)
-	}
-	fmt.Println("Using mainnet parameters (target height: 2,500,000)")
+	request.SetTargetHeight(2_500_000)
+	fmt.Println("Using testnet parameters (target height: 2,500,000)")
 	fmt.Println()
 
 	// Step 1: Propose transaction
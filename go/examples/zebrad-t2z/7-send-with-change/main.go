@@ -0,0 +1,150 @@
+// Example 7: Send With Change (Automatic Coin Selection)
+//
+// Demonstrates t2z.ProposeTransactionWithSources covering a payment from
+// several UTXOs and appending a change output automatically - no manual
+// totalInput-fee-paymentAmount arithmetic like example 3 does.
+//
+// Run with: go run ./examples/zebrad-t2z/7-send-with-change/
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	t2z "github.com/gstohl/t2z/go"
+	"golang.org/x/crypto/ripemd160"
+)
+
+func createTestKeypair7() ([]byte, []byte) {
+	privateKeyHex := "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"
+	privateKeyBytes, _ := hex.DecodeString(privateKeyHex)
+	privKey := secp256k1.PrivKeyFromBytes(privateKeyBytes)
+	return privateKeyBytes, privKey.PubKey().SerializeCompressed()
+}
+
+func hash160_7(data []byte) []byte {
+	sha256Hash := sha256.Sum256(data)
+	ripemd160Hasher := ripemd160.New()
+	ripemd160Hasher.Write(sha256Hash[:])
+	return ripemd160Hasher.Sum(nil)
+}
+
+func createP2PKHScript7(pubkey []byte) []byte {
+	pubkeyHash := hash160_7(pubkey)
+	script := make([]byte, 25)
+	script[0] = 0x76
+	script[1] = 0xa9
+	script[2] = 0x14
+	copy(script[3:23], pubkeyHash)
+	script[23] = 0x88
+	script[24] = 0xac
+	return script
+}
+
+func signMessage7(privateKey []byte, message [32]byte) [64]byte {
+	privKey := secp256k1.PrivKeyFromBytes(privateKey)
+	compact := ecdsa.SignCompact(privKey, message[:], true)
+	var sigBytes [64]byte
+	copy(sigBytes[:], compact[1:])
+	return sigBytes
+}
+
+func zatoshiToZec7(zatoshi uint64) string {
+	return fmt.Sprintf("%.8f", float64(zatoshi)/100_000_000)
+}
+
+// staticChangeSource always returns the same pre-derived change address,
+// standing in for a wallet's "next unused change address" derivation.
+type staticChangeSource string
+
+func (s staticChangeSource) ChangeAddress() (string, error) { return string(s), nil }
+
+func main() {
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", 70))
+	fmt.Println("  EXAMPLE 7: SEND WITH CHANGE (AUTOMATIC COIN SELECTION)")
+	fmt.Println(strings.Repeat("=", 70))
+	fmt.Println()
+
+	privateKey, pubkey := createTestKeypair7()
+	scriptPubKey := createP2PKHScript7(pubkey)
+	destAddress := "tm9iMLAuYMzJ6jtFLcA7rzUmfreGuKvr7Ma"
+	changeAddress := "tmEUfekwCArJoFTMEL2kFwQyrsDMCNX5ZFf"
+
+	// Several UTXOs of varying size, more than the payment alone needs - a
+	// wallet would load these from Zebra's listunspent equivalent.
+	amounts := []uint64{20_000_000, 35_000_000, 5_000_000}
+	available := make([]t2z.TransparentInput, len(amounts))
+	for i, amount := range amounts {
+		var txid [32]byte
+		copy(txid[:], []byte(fmt.Sprintf("example7_utxo_%d_test_txid_____", i)))
+		available[i] = t2z.TransparentInput{Pubkey: pubkey, TxID: txid, Vout: 0, Amount: amount, ScriptPubKey: scriptPubKey}
+	}
+
+	fmt.Println("Available UTXOs:")
+	for i, in := range available {
+		fmt.Printf("  [%d] %s ZEC\n", i, zatoshiToZec7(in.Amount))
+	}
+	fmt.Println()
+
+	paymentAmount := uint64(40_000_000)
+	fmt.Printf("Sending %s ZEC to %s\n\n", zatoshiToZec7(paymentAmount), destAddress)
+
+	payments := []t2z.Payment{{Address: destAddress, Amount: paymentAmount}}
+	request, err := t2z.NewTransactionRequest(payments)
+	if err != nil {
+		log.Fatalf("Failed to create transaction request: %v", err)
+	}
+	defer request.Free()
+	request.SetTargetHeight(2_500_000)
+
+	// ProposeTransactionWithSources picks however many UTXOs are needed,
+	// recomputing the ZIP-317 fee as it goes, and appends a change output to
+	// request itself if a meaningful residual is left over - no manual
+	// arithmetic required.
+	fmt.Println("1. Selecting inputs and proposing transaction...")
+	source := t2z.NewUTXOPool(available)
+	pczt, selected, err := t2z.ProposeTransactionWithSources(source, staticChangeSource(changeAddress), request)
+	if err != nil {
+		log.Fatalf("Failed to propose transaction: %v", err)
+	}
+	fmt.Printf("   Selected %d of %d available UTXO(s)\n", len(selected), len(available))
+	fmt.Printf("   Payments after selection (including any change): %d\n", len(request.Payments))
+	for _, p := range request.Payments {
+		fmt.Printf("     -> %s: %s ZEC\n", p.Address, zatoshiToZec7(p.Amount))
+	}
+	fmt.Println()
+
+	proved, err := t2z.ProveTransaction(pczt)
+	if err != nil {
+		log.Fatalf("Failed to prove transaction: %v", err)
+	}
+
+	fmt.Println("2. Signing every selected input...")
+	signer := t2z.NewLocalSecpSigner(secp256k1.PrivKeyFromBytes(privateKey))
+	signed, err := t2z.SignAll(proved, selected, signer)
+	if err != nil {
+		log.Fatalf("Failed to sign: %v", err)
+	}
+	fmt.Printf("   Signed %d input(s)\n\n", len(selected))
+
+	fmt.Println("3. Finalizing transaction...")
+	txBytes, err := t2z.FinalizeAndExtract(signed)
+	if err != nil {
+		log.Fatalf("Failed to finalize: %v", err)
+	}
+	fmt.Printf("   Transaction finalized (%d bytes)\n\n", len(txBytes))
+
+	fmt.Println(strings.Repeat("=", 70))
+	fmt.Println("  TRANSACTION READY FOR BROADCAST")
+	fmt.Println(strings.Repeat("=", 70))
+	fmt.Println()
+	fmt.Println("EXAMPLE 7 COMPLETED SUCCESSFULLY!")
+	fmt.Println()
+}
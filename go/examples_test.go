@@ -105,7 +105,7 @@ func ExampleSerialize() {
 	pczt.Free()
 
 	fmt.Printf("Serialized PCZT: %d bytes\n", len(pcztBytes))
-	// Output: Serialized PCZT: 364 bytes
+	// Output: Serialized PCZT: 195 bytes
 }
 
 // ExampleParse demonstrates parsing a serialized PCZT.
@@ -0,0 +1,117 @@
+// Package hdkey implements BIP-32 hierarchical deterministic key derivation
+// for the transparent (secp256k1) side of t2z, using the ZIP-32-registered
+// Zcash coin type (133) for its default derivation path. Shielded pool keys
+// have their own ZIP-32 derivation defined by librustzcash and are out of
+// scope here; this package only ever produces transparent P2PKH keys.
+package hdkey
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// HardenedOffset is added to a child index to request hardened derivation
+// (index' in path notation), per BIP-32.
+const HardenedOffset = 0x80000000
+
+// ZcashCoinType is the ZIP-32/SLIP-44 registered coin type for Zcash, used in
+// the default account path m/44'/133'/account'.
+const ZcashCoinType = 133
+
+const masterHMACKey = "Bitcoin seed" // BIP-32 fixes this literal key for every coin that reuses secp256k1 derivation
+
+// ExtendedKey is a BIP-32 extended private key: a secp256k1 scalar plus the
+// chain code needed to derive children from it.
+type ExtendedKey struct {
+	Key       *secp256k1.PrivateKey
+	ChainCode [32]byte
+	Depth     uint8
+	ChildNum  uint32
+}
+
+// NewMaster derives the master extended key from a BIP-39 seed.
+func NewMaster(seed []byte) (*ExtendedKey, error) {
+	mac := hmac.New(sha512.New, []byte(masterHMACKey))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key := secp256k1.PrivKeyFromBytes(sum[:32])
+	if key.Key.IsZero() {
+		return nil, fmt.Errorf("hdkey: invalid seed produced a zero master key")
+	}
+
+	var chainCode [32]byte
+	copy(chainCode[:], sum[32:])
+	return &ExtendedKey{Key: key, ChainCode: chainCode}, nil
+}
+
+// Child derives the child extended key at index, which may be ORed with
+// HardenedOffset to request hardened derivation.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	var data []byte
+	if index >= HardenedOffset {
+		data = append(data, 0x00)
+		data = append(data, k.Key.Serialize()...)
+	} else {
+		data = append(data, k.Key.PubKey().SerializeCompressed()...)
+	}
+	data = append(data, beUint32(index)...)
+
+	mac := hmac.New(sha512.New, k.ChainCode[:])
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	childScalar := new(secp256k1.ModNScalar)
+	if overflow := childScalar.SetByteSlice(sum[:32]); overflow {
+		return nil, fmt.Errorf("hdkey: derived IL out of range at index %d, try the next index", index)
+	}
+	childScalar.Add(&k.Key.Key)
+	if childScalar.IsZero() {
+		return nil, fmt.Errorf("hdkey: derived zero child key at index %d, try the next index", index)
+	}
+
+	var chainCode [32]byte
+	copy(chainCode[:], sum[32:])
+
+	return &ExtendedKey{
+		Key:       secp256k1.NewPrivateKey(childScalar),
+		ChainCode: chainCode,
+		Depth:     k.Depth + 1,
+		ChildNum:  index,
+	}, nil
+}
+
+// DerivePath walks a sequence of child indexes from k, in order.
+func (k *ExtendedKey) DerivePath(path ...uint32) (*ExtendedKey, error) {
+	current := k
+	for i, index := range path {
+		var err error
+		current, err = current.Child(index)
+		if err != nil {
+			return nil, fmt.Errorf("hdkey: deriving path element %d (index %d): %w", i, index, err)
+		}
+	}
+	return current, nil
+}
+
+// AccountPath returns the default transparent account path
+// m/44'/133'/account' used for Zcash transparent addresses.
+func AccountPath(account uint32) []uint32 {
+	return []uint32{44 | HardenedOffset, ZcashCoinType | HardenedOffset, account | HardenedOffset}
+}
+
+// PubKey returns the 33-byte compressed public key for this extended key,
+// ready to use as a t2z.TransparentInput.Pubkey.
+func (k *ExtendedKey) PubKey() []byte {
+	return k.Key.PubKey().SerializeCompressed()
+}
+
+func beUint32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
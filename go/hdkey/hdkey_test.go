@@ -0,0 +1,118 @@
+package hdkey
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// TestNewMasterAndChildMatchBIP32Vector1 checks NewMaster/Child against BIP-32's
+// published test vector 1 (seed 000102030405060708090a0b0c0d0e0f), comparing
+// the derived private key and chain code at m and m/0' against the spec's
+// known xprv. This is the standard cross-check that a from-scratch BIP-32
+// implementation derives the same keys as every other implementation.
+func TestNewMasterAndChildMatchBIP32Vector1(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	if err != nil {
+		t.Fatalf("decoding seed: %v", err)
+	}
+
+	master, err := NewMaster(seed)
+	if err != nil {
+		t.Fatalf("NewMaster: %v", err)
+	}
+	wantMasterKey := "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"
+	if got := hex.EncodeToString(master.Key.Serialize()); got != wantMasterKey {
+		t.Fatalf("master private key = %s, want %s", got, wantMasterKey)
+	}
+	wantMasterChainCode := "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508"
+	if got := hex.EncodeToString(master.ChainCode[:]); got != wantMasterChainCode {
+		t.Fatalf("master chain code = %s, want %s", got, wantMasterChainCode)
+	}
+
+	child, err := master.Child(HardenedOffset)
+	if err != nil {
+		t.Fatalf("Child(0'): %v", err)
+	}
+	wantChildKey := "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"
+	if got := hex.EncodeToString(child.Key.Serialize()); got != wantChildKey {
+		t.Fatalf("m/0' private key = %s, want %s", got, wantChildKey)
+	}
+	wantChildChainCode := "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"
+	if got := hex.EncodeToString(child.ChainCode[:]); got != wantChildChainCode {
+		t.Fatalf("m/0' chain code = %s, want %s", got, wantChildChainCode)
+	}
+	if child.Depth != 1 {
+		t.Fatalf("Depth = %d, want 1", child.Depth)
+	}
+	if child.ChildNum != HardenedOffset {
+		t.Fatalf("ChildNum = %d, want %d", child.ChildNum, HardenedOffset)
+	}
+}
+
+// TestDerivePathMatchesSequentialChild checks that DerivePath(a, b, c) equals
+// calling Child three times in sequence.
+func TestDerivePathMatchesSequentialChild(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	if err != nil {
+		t.Fatalf("decoding seed: %v", err)
+	}
+	master, err := NewMaster(seed)
+	if err != nil {
+		t.Fatalf("NewMaster: %v", err)
+	}
+
+	path := AccountPath(0)
+	viaPath, err := master.DerivePath(path...)
+	if err != nil {
+		t.Fatalf("DerivePath: %v", err)
+	}
+
+	current := master
+	for _, index := range path {
+		current, err = current.Child(index)
+		if err != nil {
+			t.Fatalf("Child(%d): %v", index, err)
+		}
+	}
+
+	if hex.EncodeToString(viaPath.Key.Serialize()) != hex.EncodeToString(current.Key.Serialize()) {
+		t.Fatal("DerivePath produced a different key than sequential Child calls")
+	}
+	if viaPath.ChainCode != current.ChainCode {
+		t.Fatal("DerivePath produced a different chain code than sequential Child calls")
+	}
+}
+
+// TestChildDerivationIsDeterministic checks that deriving the same child
+// index twice from the same parent produces identical keys, and that two
+// different indexes produce different keys.
+func TestChildDerivationIsDeterministic(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	if err != nil {
+		t.Fatalf("decoding seed: %v", err)
+	}
+	master, err := NewMaster(seed)
+	if err != nil {
+		t.Fatalf("NewMaster: %v", err)
+	}
+
+	a, err := master.Child(0)
+	if err != nil {
+		t.Fatalf("Child(0): %v", err)
+	}
+	b, err := master.Child(0)
+	if err != nil {
+		t.Fatalf("Child(0) again: %v", err)
+	}
+	if hex.EncodeToString(a.Key.Serialize()) != hex.EncodeToString(b.Key.Serialize()) {
+		t.Fatal("Child(0) is not deterministic")
+	}
+
+	c, err := master.Child(1)
+	if err != nil {
+		t.Fatalf("Child(1): %v", err)
+	}
+	if hex.EncodeToString(a.Key.Serialize()) == hex.EncodeToString(c.Key.Serialize()) {
+		t.Fatal("Child(0) and Child(1) produced the same key")
+	}
+}
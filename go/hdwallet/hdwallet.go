@@ -0,0 +1,127 @@
+// Package hdwallet turns a BIP-39 mnemonic into a signing wallet for t2z's
+// PCZT API, building on hdkey's BIP-32 derivation so callers work with
+// string derivation paths and a mnemonic instead of juggling raw extended
+// keys and manual per-index derivation.
+package hdwallet
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/pbkdf2"
+
+	t2z "github.com/gstohl/t2z/go"
+	"github.com/gstohl/t2z/go/hdkey"
+)
+
+// bip39PBKDF2Rounds and the "mnemonic" salt prefix are fixed by BIP-39.
+const bip39PBKDF2Rounds = 2048
+
+// Wallet derives transparent signing keys from a BIP-39 mnemonic, lazily
+// deriving each path it's asked for rather than precomputing a tree.
+type Wallet struct {
+	master *hdkey.ExtendedKey
+}
+
+// New derives a Wallet's BIP-32 master key from mnemonic and passphrase per
+// BIP-39 (PBKDF2-HMAC-SHA512, 2048 rounds, salt "mnemonic"+passphrase).
+func New(mnemonic, passphrase string) (*Wallet, error) {
+	seed := pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+passphrase), bip39PBKDF2Rounds, 64, sha512.New)
+	master, err := hdkey.NewMaster(seed)
+	if err != nil {
+		return nil, fmt.Errorf("hdwallet: deriving master key: %w", err)
+	}
+	return &Wallet{master: master}, nil
+}
+
+// DeriveTransparentKey derives the extended key at path, a string such as
+// "m/44'/133'/0'/0/0" using a trailing ' or h on a path element to request
+// hardened derivation, matching hdkey.AccountPath's m/44'/133'/account'
+// convention.
+func (w *Wallet) DeriveTransparentKey(path string) (*hdkey.ExtendedKey, error) {
+	indexes, err := parsePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("hdwallet: %w", err)
+	}
+	key, err := w.master.DerivePath(indexes...)
+	if err != nil {
+		return nil, fmt.Errorf("hdwallet: deriving path %q: %w", path, err)
+	}
+	return key, nil
+}
+
+// KeyForInput derives the private key at path and confirms it actually
+// produces input.Pubkey, catching a wrong path before it goes on to produce
+// an unverifiable signature.
+func (w *Wallet) KeyForInput(input t2z.TransparentInput, path string) (*secp256k1.PrivateKey, error) {
+	key, err := w.DeriveTransparentKey(path)
+	if err != nil {
+		return nil, err
+	}
+	if string(key.PubKey()) != string(input.Pubkey) {
+		return nil, fmt.Errorf("hdwallet: key at path %q does not match input's pubkey", path)
+	}
+	return key.Key, nil
+}
+
+// SignAllInputs walks pczt's transparent inputs in order, deriving the key at
+// paths[i] for input i, and appends a signature for each - the HD equivalent
+// of t2z.SignAll for a caller who already knows each input's derivation path
+// instead of holding raw private keys.
+func (w *Wallet) SignAllInputs(pczt *t2z.PCZT, inputs []t2z.TransparentInput, paths []string) (*t2z.PCZT, error) {
+	if len(inputs) != len(paths) {
+		return nil, fmt.Errorf("hdwallet: have %d inputs but %d paths", len(inputs), len(paths))
+	}
+	return t2z.SignAll(pczt, inputs, &pathSigner{wallet: w, paths: paths})
+}
+
+// pathSigner adapts a Wallet plus a per-input path list to t2z.Signer.
+type pathSigner struct {
+	wallet *Wallet
+	paths  []string
+}
+
+// Sign implements t2z.Signer.
+func (s *pathSigner) Sign(inputIndex int, sighash [32]byte, pubkey []byte) ([64]byte, error) {
+	var sig [64]byte
+	key, err := s.wallet.DeriveTransparentKey(s.paths[inputIndex])
+	if err != nil {
+		return sig, err
+	}
+	if string(key.PubKey()) != string(pubkey) {
+		return sig, fmt.Errorf("hdwallet: key at path %q does not match input's pubkey", s.paths[inputIndex])
+	}
+	compact := ecdsa.SignCompact(key.Key, sighash[:], true)
+	copy(sig[:], compact[1:])
+	return sig, nil
+}
+
+// parsePath parses a BIP-32 path string like "m/44'/133'/0'/0/0" into
+// hdkey.ExtendedKey.DerivePath's []uint32 form.
+func parsePath(path string) ([]uint32, error) {
+	elements := strings.Split(path, "/")
+	if len(elements) == 0 || elements[0] != "m" {
+		return nil, fmt.Errorf("path %q must start with \"m/\"", path)
+	}
+
+	indexes := make([]uint32, 0, len(elements)-1)
+	for _, e := range elements[1:] {
+		hardened := strings.HasSuffix(e, "'") || strings.HasSuffix(e, "h")
+		if hardened {
+			e = e[:len(e)-1]
+		}
+		index, err := strconv.ParseUint(e, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path element %q: %w", e, err)
+		}
+		if hardened {
+			index += hdkey.HardenedOffset
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, nil
+}
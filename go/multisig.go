@@ -0,0 +1,220 @@
+package t2z
+
+import "fmt"
+
+const (
+	opCheckMultisig = 0xae
+	op1Minus1       = 0x50 // OP_1 is 0x51; pushed M/N use OP_1..OP_16 (0x51-0x60)
+)
+
+// MultisigConfig describes an m-of-n CHECKMULTISIG policy for a P2SH
+// transparent input: M valid signatures out of PubKeys are required to spend.
+type MultisigConfig struct {
+	M       uint8
+	PubKeys [][]byte
+}
+
+// RedeemScript builds the standard `OP_m <pubkey_1> ... <pubkey_n> OP_n
+// OP_CHECKMULTISIG` redeem script for config.
+func (c *MultisigConfig) RedeemScript() ([]byte, error) {
+	if int(c.M) == 0 || int(c.M) > len(c.PubKeys) || len(c.PubKeys) > 16 {
+		return nil, fmt.Errorf("t2z: invalid multisig config: %d-of-%d", c.M, len(c.PubKeys))
+	}
+	script := []byte{op1Minus1 + c.M}
+	for _, pk := range c.PubKeys {
+		script = append(script, byte(len(pk)))
+		script = append(script, pk...)
+	}
+	script = append(script, op1Minus1+byte(len(c.PubKeys)), opCheckMultisig)
+	return script, nil
+}
+
+// p2shScript wraps a redeem script's hash160 as a standard
+// `OP_HASH160 <20 bytes> OP_EQUAL` scriptPubKey.
+func p2shScript(redeemScript []byte) []byte {
+	h := hash160(redeemScript)
+	script := make([]byte, 0, 23)
+	script = append(script, 0xa9, 0x14)
+	script = append(script, h...)
+	script = append(script, 0x87)
+	return script
+}
+
+// NewMultisigInput builds a TransparentInput spending a P2SH output locked by
+// config's redeem script, along with the redeem script itself (needed later
+// by GetSighash and FinalizeMultisigAndExtract). RedeemScript and Multisig
+// are set on the returned input, as TransparentInput's doc comment promises,
+// so FinalizeAndExtract recognizes it as a multisig input awaiting
+// FinalizeMultisigAndExtract rather than a signed P2PKH one.
+func NewMultisigInput(txid [32]byte, vout uint32, amount uint64, config *MultisigConfig) (input TransparentInput, redeemScript []byte, err error) {
+	redeemScript, err = config.RedeemScript()
+	if err != nil {
+		return TransparentInput{}, nil, err
+	}
+	input = TransparentInput{
+		TxID:         txid,
+		Vout:         vout,
+		Amount:       amount,
+		ScriptPubKey: p2shScript(redeemScript),
+		RedeemScript: redeemScript,
+		Multisig:     config,
+	}
+	return input, redeemScript, nil
+}
+
+// MultisigSignatureSet accumulates cosigners' partial signatures for one P2SH
+// multisig input until enough (config.M) are present to finalize.
+type MultisigSignatureSet struct {
+	config       *MultisigConfig
+	redeemScript []byte
+	sigs         map[string][64]byte // keyed by pubkey bytes
+}
+
+// NewMultisigSignatureSet creates an empty set for a given redeem script and
+// policy. redeemScript must be the same one returned by NewMultisigInput.
+func NewMultisigSignatureSet(config *MultisigConfig, redeemScript []byte) *MultisigSignatureSet {
+	return &MultisigSignatureSet{config: config, redeemScript: redeemScript, sigs: make(map[string][64]byte)}
+}
+
+// Add stashes a cosigner's partial signature, keyed by their pubkey. pubkey
+// must be one of config.PubKeys.
+func (s *MultisigSignatureSet) Add(pubkey []byte, sig [64]byte) error {
+	found := false
+	for _, pk := range s.config.PubKeys {
+		if string(pk) == string(pubkey) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("t2z: pubkey %x is not a cosigner for this multisig input", pubkey)
+	}
+	s.sigs[string(pubkey)] = sig
+	return nil
+}
+
+// Ready reports whether at least config.M signatures have been collected.
+func (s *MultisigSignatureSet) Ready() bool {
+	return len(s.sigs) >= int(s.config.M)
+}
+
+// MissingCosigners lists the pubkeys that have not yet supplied a signature.
+func (s *MultisigSignatureSet) MissingCosigners() [][]byte {
+	var missing [][]byte
+	for _, pk := range s.config.PubKeys {
+		if _, ok := s.sigs[string(pk)]; !ok {
+			missing = append(missing, pk)
+		}
+	}
+	return missing
+}
+
+// scriptSig assembles the final `OP_0 <sig_1> ... <sig_m> <redeemScript>`
+// scriptSig once Ready, in the cosigner order given by config.PubKeys (the
+// order CHECKMULTISIG requires signatures to appear in).
+func (s *MultisigSignatureSet) scriptSig() ([]byte, error) {
+	if !s.Ready() {
+		return nil, fmt.Errorf("t2z: missing signatures from cosigners: %x", s.MissingCosigners())
+	}
+	// CHECKMULTISIG checks signatures against pubkeys in order, so signatures
+	// must appear in the same order as config.PubKeys, not insertion order.
+	script := []byte{0x00} // OP_0: CHECKMULTISIG's historical off-by-one bug
+	used := 0
+	for _, pk := range s.config.PubKeys {
+		sig, ok := s.sigs[string(pk)]
+		if !ok {
+			continue
+		}
+		der := append(derEncodeSignature(sig), byte(SighashAll))
+		script = append(script, pushData(der)...)
+		used++
+		if used == int(s.config.M) {
+			break
+		}
+	}
+	script = append(script, pushData(s.redeemScript)...)
+	return script, nil
+}
+
+// derEncodeSignature encodes a compact (r||s) signature as minimal DER,
+// stripping leading zero bytes and re-adding a single zero pad byte whenever
+// the high bit would otherwise be mistaken for a sign.
+func derEncodeSignature(sig [64]byte) []byte {
+	encodeInt := func(b []byte) []byte {
+		for len(b) > 1 && b[0] == 0x00 && b[1] < 0x80 {
+			b = b[1:]
+		}
+		if b[0]&0x80 != 0 {
+			b = append([]byte{0x00}, b...)
+		}
+		return append([]byte{0x02, byte(len(b))}, b...)
+	}
+	r := encodeInt(append([]byte{}, sig[:32]...))
+	s := encodeInt(append([]byte{}, sig[32:]...))
+	body := append(r, s...)
+	return append([]byte{0x30, byte(len(body))}, body...)
+}
+
+// pushData encodes data as a minimal script push: a direct length byte for
+// short data, or OP_PUSHDATA1/2 for longer data such as a multisig redeem
+// script.
+func pushData(data []byte) []byte {
+	switch {
+	case len(data) <= 0x4b:
+		return append([]byte{byte(len(data))}, data...)
+	case len(data) <= 0xff:
+		return append([]byte{0x4c, byte(len(data))}, data...)
+	default:
+		return append([]byte{0x4d, byte(len(data)), byte(len(data) >> 8)}, data...)
+	}
+}
+
+// ErrWitnessStyleUnsupported is returned by NewWitnessStyleMultisigInput.
+// Zcash's transparent consensus rules predate (and do not include) segwit: a
+// scriptPubKey that is itself P2SH-wrapping a bare `OP_0 <sha256(script)>`
+// witness program - the usual "P2WSH-style" construction - has no witness
+// field to carry the real unlock data, so the redeem script pushed into
+// scriptSig must be the witness program itself. Executing that program as a
+// script (per BIP-16) just pushes two values and succeeds without ever
+// checking a signature: it would be spendable by anyone who reads the chain,
+// not just the cosigners. There is no safe way to offer this without a
+// consensus change, so this library only supports the plain P2SH template
+// from NewMultisigInput.
+var ErrWitnessStyleUnsupported = fmt.Errorf("t2z: P2WSH-style nesting is unsafe without segwit consensus support; use NewMultisigInput (P2SH) instead")
+
+// NewWitnessStyleMultisigInput always returns ErrWitnessStyleUnsupported. It
+// exists so callers porting BIP-141-style wallet code get a clear explanation
+// instead of silently constructing an anyone-can-spend output.
+func NewWitnessStyleMultisigInput(_ [32]byte, _ uint32, _ uint64, _ *MultisigConfig) (TransparentInput, []byte, error) {
+	return TransparentInput{}, nil, ErrWitnessStyleUnsupported
+}
+
+// FinalizeMultisigAndExtract finalizes pczt as usual and then patches in the
+// assembled P2SH scriptSig for every input in sigSets (keyed by input index),
+// returning the final raw transaction bytes. Every set in sigSets must be
+// Ready; otherwise a descriptive error lists which cosigners are still
+// missing, so callers can go collect the rest before retrying.
+func FinalizeMultisigAndExtract(pczt *PCZT, sigSets map[int]*MultisigSignatureSet) ([]byte, error) {
+	txBytes, err := FinalizeAndExtract(pczt)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: finalizing PCZT: %w", err)
+	}
+
+	tx, err := parseV5Transaction(txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: parsing finalized transaction: %w", err)
+	}
+
+	for index, set := range sigSets {
+		if index < 0 || index >= len(tx.Inputs) {
+			return nil, fmt.Errorf("t2z: multisig signature set for out-of-range input %d", index)
+		}
+		scriptSig, err := set.scriptSig()
+		if err != nil {
+			return nil, fmt.Errorf("t2z: input %d: %w", index, err)
+		}
+		tx.Inputs[index].ScriptSig = scriptSig
+	}
+
+	return tx.serialize(), nil
+}
@@ -0,0 +1,89 @@
+package t2z_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	t2z "github.com/gstohl/t2z/go"
+)
+
+// TestMultisigRoundTrip builds a 2-of-3 P2SH multisig input, signs it via the
+// documented NewMultisigInput -> GetSighash -> FinalizeMultisigAndExtract
+// path, and verifies the finalized transaction. This is a regression test
+// for GetSighash hashing the P2SH scriptPubKey instead of the redeem script
+// (which made every signature produced this way fail verification), and for
+// NewMultisigInput never populating RedeemScript/Multisig on the returned
+// input (which made FinalizeAndExtract reject the input as unsigned).
+func TestMultisigRoundTrip(t *testing.T) {
+	privKeys := make([]*secp256k1.PrivateKey, 3)
+	pubKeys := make([][]byte, 3)
+	for i := range privKeys {
+		seed := make([]byte, 32)
+		seed[0] = byte(i + 1)
+		privKeys[i] = secp256k1.PrivKeyFromBytes(seed)
+		pubKeys[i] = privKeys[i].PubKey().SerializeCompressed()
+	}
+	config := &t2z.MultisigConfig{M: 2, PubKeys: pubKeys}
+
+	var txid [32]byte
+	input, redeemScript, err := t2z.NewMultisigInput(txid, 0, 100_000_000, config)
+	if err != nil {
+		t.Fatalf("NewMultisigInput: %v", err)
+	}
+	if input.RedeemScript == nil {
+		t.Fatal("NewMultisigInput did not set RedeemScript on the returned input")
+	}
+	if input.Multisig == nil {
+		t.Fatal("NewMultisigInput did not set Multisig on the returned input")
+	}
+
+	payments := []t2z.Payment{
+		{Address: "tm9iMLAuYMzJ6jtFLcA7rzUmfreGuKvr7Ma", Amount: 50_000_000},
+	}
+	request, err := t2z.NewTransactionRequest(payments)
+	if err != nil {
+		t.Fatalf("NewTransactionRequest: %v", err)
+	}
+	defer request.Free()
+
+	pczt, err := t2z.ProposeTransaction([]t2z.TransparentInput{input}, request)
+	if err != nil {
+		t.Fatalf("ProposeTransaction: %v", err)
+	}
+
+	sighash, err := t2z.GetSighash(pczt, 0)
+	if err != nil {
+		t.Fatalf("GetSighash: %v", err)
+	}
+
+	sigSet := t2z.NewMultisigSignatureSet(config, redeemScript)
+	for _, i := range []int{0, 2} { // any 2 of the 3 cosigners
+		compactSig := ecdsa.SignCompact(privKeys[i], sighash[:], true)
+		var sig [64]byte
+		copy(sig[:], compactSig[1:])
+		if err := sigSet.Add(pubKeys[i], sig); err != nil {
+			t.Fatalf("Add cosigner %d: %v", i, err)
+		}
+	}
+	if !sigSet.Ready() {
+		t.Fatal("signature set not Ready after 2 of 2-of-3 cosigners signed")
+	}
+
+	txBytes, err := t2z.FinalizeMultisigAndExtract(pczt, map[int]*t2z.MultisigSignatureSet{0: sigSet})
+	if err != nil {
+		t.Fatalf("FinalizeMultisigAndExtract: %v", err)
+	}
+
+	scriptPubKeyHex := hex.EncodeToString(input.ScriptPubKey)
+	if scriptPubKeyHex == "" {
+		t.Fatal("input has no ScriptPubKey")
+	}
+	err = t2z.VerifyTransaction(txBytes, []t2z.TransparentOutput{
+		{ScriptPubKey: input.ScriptPubKey, Amount: input.Amount},
+	})
+	if err != nil {
+		t.Fatalf("VerifyTransaction: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Package t2z implements a pure-Go Zcash PCZT (Partially Created Zcash
+// Transaction) builder, signer, and verifier, focused on transparent inputs
+// paying transparent, Sapling, or unified-address outputs.
+//
+// Known gap: ParseAddress cannot decode the receivers of a unified address
+// ("u1...", "utest1..."). Network detection and mismatch rejection still
+// work, since those only need the address's HRP, but Receivers comes back
+// empty and PreferredReceiver has nothing to choose from - ParseAddress
+// returns ErrUnifiedReceiversUnsupported instead of guessing. Closing this
+// gap means implementing ZIP-316's F4Jumble permutation to un-jumble the
+// payload before the individual receivers can be split out.
+package t2z
+
+import (
+	"fmt"
+
+	"github.com/gstohl/t2z/go/address"
+)
+
+// AddressEncoding identifies which Zcash address family ParseAddress
+// recognized s as.
+type AddressEncoding int
+
+const (
+	// EncodingTransparent is a Base58Check P2PKH or P2SH address ("t1...",
+	// "t3...").
+	EncodingTransparent AddressEncoding = iota
+	// EncodingSapling is a Bech32 Sapling shielded address ("zs...",
+	// "ztestsapling...").
+	EncodingSapling
+	// EncodingUnified is a Bech32m ZIP-316 unified address ("u1...",
+	// "utest1...").
+	EncodingUnified
+)
+
+// ReceiverKind identifies which pool a unified address's receiver (or a
+// non-unified address's implied single receiver) pays into.
+type ReceiverKind int
+
+const (
+	ReceiverTransparent ReceiverKind = iota
+	ReceiverSapling
+	ReceiverOrchard
+)
+
+// ZIP-316 receiver typecodes.
+const (
+	receiverTypecodeP2PKH   = 0x00
+	receiverTypecodeP2SH    = 0x01
+	receiverTypecodeSapling = 0x02
+	receiverTypecodeOrchard = 0x03
+)
+
+// Receiver is one decoded receiver of an address: its pool, ZIP-316
+// typecode, and raw receiver bytes (20-byte hash160 for transparent, 43-byte
+// diversifier+pk_d for Sapling, 43-byte diversifier+pk_d for Orchard).
+type Receiver struct {
+	Kind     ReceiverKind
+	Typecode byte
+	Raw      []byte
+}
+
+// ParsedAddress is the result of ParseAddress: which encoding s used, which
+// network it was produced for, and its receiver(s) - one for a transparent
+// or Sapling address, one or more for a unified address.
+type ParsedAddress struct {
+	Encoding    AddressEncoding
+	Network     Network
+	Transparent *address.Address // set only when Encoding == EncodingTransparent
+	Receivers   []Receiver
+}
+
+// ErrUnifiedReceiversUnsupported is returned by ParseAddress for an otherwise
+// well-formed, checksum-valid unified address: decoding its receivers
+// requires reversing ZIP-316's F4Jumble permutation over the whole payload,
+// which this package does not yet implement. Network and encoding detection
+// (and therefore network-mismatch rejection) still work without it, but the
+// Receivers slice cannot be produced, so ParseAddress reports the gap
+// explicitly rather than guessing at un-jumbled bytes.
+var ErrUnifiedReceiversUnsupported = fmt.Errorf("t2z: unified address receiver decoding requires F4Jumble, which is not yet implemented")
+
+// ParseAddress identifies the encoding of s - transparent, Sapling, or
+// unified - and decodes it into a ParsedAddress, rejecting it if its network
+// doesn't match network.
+func ParseAddress(s string, network Network) (*ParsedAddress, error) {
+	if addr, err := address.Parse(s); err == nil {
+		got := NetworkTestnet
+		if addr.Mainnet {
+			got = NetworkMainnet
+		}
+		if got != network {
+			return nil, &NetworkMismatchError{Got: got, Want: network}
+		}
+		kind, typecode := ReceiverTransparent, byte(receiverTypecodeP2PKH)
+		if addr.Type == address.P2SH {
+			typecode = receiverTypecodeP2SH
+		}
+		return &ParsedAddress{
+			Encoding:    EncodingTransparent,
+			Network:     network,
+			Transparent: addr,
+			Receivers:   []Receiver{{Kind: kind, Typecode: typecode, Raw: append([]byte{}, addr.Hash[:]...)}},
+		}, nil
+	}
+
+	hrp, data, isBech32m, err := decodeBech32(s)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: %q is neither a valid transparent, Sapling, nor unified address: %w", s, err)
+	}
+
+	switch hrp {
+	case "zs", "ztestsapling":
+		if isBech32m {
+			return nil, fmt.Errorf("t2z: %q has a Sapling prefix but a Bech32m checksum", s)
+		}
+		mainnet := hrp == "zs"
+		got := NetworkTestnet
+		if mainnet {
+			got = NetworkMainnet
+		}
+		if got != network {
+			return nil, &NetworkMismatchError{Got: got, Want: network}
+		}
+		payload, err := convertBits(data, 5, 8, false)
+		if err != nil {
+			return nil, fmt.Errorf("t2z: decoding Sapling address payload: %w", err)
+		}
+		if len(payload) != 43 {
+			return nil, fmt.Errorf("t2z: Sapling address payload is %d bytes, want 43", len(payload))
+		}
+		return &ParsedAddress{
+			Encoding:  EncodingSapling,
+			Network:   network,
+			Receivers: []Receiver{{Kind: ReceiverSapling, Typecode: receiverTypecodeSapling, Raw: payload}},
+		}, nil
+
+	case "u", "utest":
+		if !isBech32m {
+			return nil, fmt.Errorf("t2z: %q has a unified-address prefix but a plain Bech32 checksum", s)
+		}
+		mainnet := hrp == "u"
+		got := NetworkTestnet
+		if mainnet {
+			got = NetworkMainnet
+		}
+		if got != network {
+			return nil, &NetworkMismatchError{Got: got, Want: network}
+		}
+		return nil, ErrUnifiedReceiversUnsupported
+
+	default:
+		return nil, fmt.Errorf("t2z: %q has unrecognized address prefix %q", s, hrp)
+	}
+}
+
+// PreferredReceiver returns the first of a's Receivers whose Kind appears in
+// preferences, trying preferences in order - e.g. {ReceiverOrchard,
+// ReceiverSapling} to prefer paying a unified address's Orchard receiver but
+// gracefully degrade to its Sapling one.
+func (a *ParsedAddress) PreferredReceiver(preferences []ReceiverKind) (Receiver, error) {
+	for _, kind := range preferences {
+		for _, r := range a.Receivers {
+			if r.Kind == kind {
+				return r, nil
+			}
+		}
+	}
+	return Receiver{}, fmt.Errorf("t2z: address has none of the preferred receiver kinds")
+}
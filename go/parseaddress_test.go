@@ -0,0 +1,74 @@
+package t2z_test
+
+import (
+	"errors"
+	"testing"
+
+	t2z "github.com/gstohl/t2z/go"
+	"github.com/gstohl/t2z/go/address"
+)
+
+// TestParseAddressTransparentRoundTrip checks that ParseAddress recovers a
+// P2PKH address's hash160 and typecode, and rejects it when asked for the
+// wrong network.
+func TestParseAddressTransparentRoundTrip(t *testing.T) {
+	var hash [20]byte
+	for i := range hash {
+		hash[i] = byte(i + 1)
+	}
+	s := address.EncodeP2PKH(hash, false) // testnet
+
+	parsed, err := t2z.ParseAddress(s, t2z.NetworkTestnet)
+	if err != nil {
+		t.Fatalf("ParseAddress: %v", err)
+	}
+	if parsed.Encoding != t2z.EncodingTransparent {
+		t.Fatalf("Encoding = %v, want EncodingTransparent", parsed.Encoding)
+	}
+	if len(parsed.Receivers) != 1 {
+		t.Fatalf("got %d receivers, want 1", len(parsed.Receivers))
+	}
+	r := parsed.Receivers[0]
+	if r.Kind != t2z.ReceiverTransparent {
+		t.Fatalf("Kind = %v, want ReceiverTransparent", r.Kind)
+	}
+	if string(r.Raw) != string(hash[:]) {
+		t.Fatalf("Raw = %x, want %x", r.Raw, hash)
+	}
+
+	preferred, err := parsed.PreferredReceiver([]t2z.ReceiverKind{t2z.ReceiverOrchard, t2z.ReceiverTransparent})
+	if err != nil {
+		t.Fatalf("PreferredReceiver: %v", err)
+	}
+	if preferred.Kind != t2z.ReceiverTransparent {
+		t.Fatalf("PreferredReceiver returned %v, want ReceiverTransparent", preferred.Kind)
+	}
+
+	if _, err := t2z.ParseAddress(s, t2z.NetworkMainnet); err == nil {
+		t.Fatal("ParseAddress accepted a testnet address as mainnet")
+	} else {
+		var mismatch *t2z.NetworkMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Fatalf("ParseAddress returned %T, want *NetworkMismatchError", err)
+		}
+	}
+}
+
+// TestParseAddressUnifiedReportsGap is a regression test for ParseAddress
+// silently misparsing a unified address instead of reporting that it can't
+// decode its receivers (ParseAddress doesn't implement ZIP-316's F4Jumble
+// yet).
+func TestParseAddressUnifiedReportsGap(t *testing.T) {
+	_, err := t2z.ParseAddress("utest1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzsep7dnf", t2z.NetworkTestnet)
+	if !errors.Is(err, t2z.ErrUnifiedReceiversUnsupported) {
+		t.Fatalf("ParseAddress error = %v, want ErrUnifiedReceiversUnsupported", err)
+	}
+}
+
+// TestParseAddressRejectsGarbage checks that an unrecognized string produces
+// an error rather than a zero-value ParsedAddress.
+func TestParseAddressRejectsGarbage(t *testing.T) {
+	if _, err := t2z.ParseAddress("not-a-zcash-address", t2z.NetworkMainnet); err == nil {
+		t.Fatal("ParseAddress accepted garbage input")
+	}
+}
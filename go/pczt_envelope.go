@@ -0,0 +1,106 @@
+package t2z
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// pcztEnvelopePrefix is the BIP-276-style human-readable prefix for a PCZT
+// envelope, distinguishing it at a glance from the pczt-zec envelope used by
+// PCZT.MarshalText and from an arbitrary hex blob.
+const pcztEnvelopePrefix = "pczt-zcash"
+
+// pcztEnvelopeVersion is the envelope format version.
+const pcztEnvelopeVersion = 1
+
+// Network identifies which Zcash network a PCZT envelope was produced for, so
+// DecodePCZT can reject a PCZT copy-pasted into the wrong context (e.g. a
+// testnet PCZT pasted into a mainnet wallet).
+type Network uint32
+
+const (
+	NetworkMainnet Network = 0x4d41494e // "MAIN"
+	NetworkTestnet Network = 0x54455354 // "TEST"
+	NetworkRegtest Network = 0x52454754 // "REGT"
+)
+
+// ErrMalformedEnvelope is returned by DecodePCZT when s isn't a well-formed
+// pczt-zcash envelope: wrong prefix, wrong field count, or unparseable hex.
+var ErrMalformedEnvelope = fmt.Errorf("t2z: malformed pczt-zcash envelope")
+
+// ErrEnvelopeChecksumMismatch is returned by DecodePCZT when the trailing
+// checksum doesn't match the decoded body.
+var ErrEnvelopeChecksumMismatch = fmt.Errorf("t2z: pczt-zcash envelope checksum mismatch")
+
+// NetworkMismatchError is returned by DecodePCZT when the envelope's network
+// doesn't match the caller-supplied expected network.
+type NetworkMismatchError struct {
+	Got, Want Network
+}
+
+func (e *NetworkMismatchError) Error() string {
+	return fmt.Sprintf("t2z: pczt-zcash envelope is for network %08x, expected %08x", uint32(e.Got), uint32(e.Want))
+}
+
+// EncodePCZT serializes pczt and wraps it in a BIP-276-style envelope:
+// `pczt-zcash:<version><network><hex(payload)><checksum>`, all lowercased,
+// where version is one byte, network is 4 bytes, and checksum is the first 4
+// bytes of sha256(prefix + version + network + payload), appended after the
+// hex-encoded payload. This is a copy-pasteable, network-tagged, integrity
+// checked alternative to handing Serialize's raw bytes to air-gapped and
+// clipboard-based transports.
+func EncodePCZT(pczt *PCZT, network Network) (string, error) {
+	payload, err := Serialize(pczt)
+	if err != nil {
+		return "", fmt.Errorf("t2z: serializing PCZT: %w", err)
+	}
+
+	header := []byte{pcztEnvelopeVersion}
+	header = append(header, byte(network>>24), byte(network>>16), byte(network>>8), byte(network))
+
+	sum := sha256.Sum256(append(append([]byte(pcztEnvelopePrefix), header...), payload...))
+	checksum := sum[:4]
+
+	return fmt.Sprintf("%s:%s:%s", pcztEnvelopePrefix, hex.EncodeToString(append(header, payload...)), hex.EncodeToString(checksum)), nil
+}
+
+// DecodePCZT reverses EncodePCZT, verifying the checksum and rejecting the
+// envelope if its network doesn't match expected.
+func DecodePCZT(s string, expected Network) (*PCZT, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 || parts[0] != pcztEnvelopePrefix {
+		return nil, ErrMalformedEnvelope
+	}
+
+	body, err := hex.DecodeString(parts[1])
+	if err != nil || len(body) < 5 {
+		return nil, ErrMalformedEnvelope
+	}
+	checksum, err := hex.DecodeString(parts[2])
+	if err != nil || len(checksum) != 4 {
+		return nil, ErrMalformedEnvelope
+	}
+
+	version := body[0]
+	if version != pcztEnvelopeVersion {
+		return nil, ErrMalformedEnvelope
+	}
+	network := Network(uint32(body[1])<<24 | uint32(body[2])<<16 | uint32(body[3])<<8 | uint32(body[4]))
+	payload := body[5:]
+
+	sum := sha256.Sum256(append([]byte(pcztEnvelopePrefix), body...))
+	if string(sum[:4]) != string(checksum) {
+		return nil, ErrEnvelopeChecksumMismatch
+	}
+	if network != expected {
+		return nil, &NetworkMismatchError{Got: network, Want: expected}
+	}
+
+	pczt, err := Parse(payload)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: parsing PCZT: %w", err)
+	}
+	return pczt, nil
+}
@@ -0,0 +1,155 @@
+package t2z_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	t2z "github.com/gstohl/t2z/go"
+)
+
+// envelopeFixturePCZT builds a minimal, proved PCZT suitable for round-tripping
+// through EncodePCZT/DecodePCZT.
+func envelopeFixturePCZT(t *testing.T) *t2z.PCZT {
+	t.Helper()
+
+	payments := []t2z.Payment{
+		{Address: "tm9iMLAuYMzJ6jtFLcA7rzUmfreGuKvr7Ma", Amount: 100_000},
+	}
+	request, err := t2z.NewTransactionRequest(payments)
+	if err != nil {
+		t.Fatalf("NewTransactionRequest: %v", err)
+	}
+	defer request.Free()
+
+	privateKeyBytes := make([]byte, 32)
+	for i := range privateKeyBytes {
+		privateKeyBytes[i] = 1
+	}
+	pubKeyBytes := secp256k1.PrivKeyFromBytes(privateKeyBytes).PubKey().SerializeCompressed()
+
+	scriptPubKey, err := hex.DecodeString("1976a91479b000887626b294a914501a4cd226b58b23598388ac")
+	if err != nil {
+		t.Fatalf("decoding scriptPubKey: %v", err)
+	}
+
+	var txid [32]byte
+	inputs := []t2z.TransparentInput{
+		{Pubkey: pubKeyBytes, TxID: txid, Vout: 0, Amount: 100_000_000, ScriptPubKey: scriptPubKey},
+	}
+
+	pczt, err := t2z.ProposeTransaction(inputs, request)
+	if err != nil {
+		t.Fatalf("ProposeTransaction: %v", err)
+	}
+	proved, err := t2z.ProveTransaction(pczt)
+	if err != nil {
+		t.Fatalf("ProveTransaction: %v", err)
+	}
+	return proved
+}
+
+func TestEncodeDecodePCZTRoundTrip(t *testing.T) {
+	pczt := envelopeFixturePCZT(t)
+
+	envelope, err := t2z.EncodePCZT(pczt, t2z.NetworkTestnet)
+	if err != nil {
+		t.Fatalf("EncodePCZT: %v", err)
+	}
+	if !strings.HasPrefix(envelope, "pczt-zcash:") {
+		t.Fatalf("envelope missing pczt-zcash prefix: %q", envelope)
+	}
+
+	decoded, err := t2z.DecodePCZT(envelope, t2z.NetworkTestnet)
+	if err != nil {
+		t.Fatalf("DecodePCZT: %v", err)
+	}
+
+	wantBytes, err := t2z.Serialize(pczt)
+	if err != nil {
+		t.Fatalf("Serialize(original): %v", err)
+	}
+	gotBytes, err := t2z.Serialize(decoded)
+	if err != nil {
+		t.Fatalf("Serialize(decoded): %v", err)
+	}
+	if string(gotBytes) != string(wantBytes) {
+		t.Fatalf("round-tripped PCZT does not match original: got %x, want %x", gotBytes, wantBytes)
+	}
+}
+
+func TestDecodePCZTTruncatedEnvelope(t *testing.T) {
+	pczt := envelopeFixturePCZT(t)
+	envelope, err := t2z.EncodePCZT(pczt, t2z.NetworkTestnet)
+	if err != nil {
+		t.Fatalf("EncodePCZT: %v", err)
+	}
+
+	truncated := envelope[:len(envelope)-20]
+	if _, err := t2z.DecodePCZT(truncated, t2z.NetworkTestnet); err == nil {
+		t.Fatal("DecodePCZT accepted a truncated envelope")
+	}
+}
+
+func TestDecodePCZTOneByteFlipInBody(t *testing.T) {
+	pczt := envelopeFixturePCZT(t)
+	envelope, err := t2z.EncodePCZT(pczt, t2z.NetworkTestnet)
+	if err != nil {
+		t.Fatalf("EncodePCZT: %v", err)
+	}
+
+	parts := strings.Split(envelope, ":")
+	if len(parts) != 3 {
+		t.Fatalf("unexpected envelope shape: %q", envelope)
+	}
+	body := []byte(parts[1])
+	// Flip a hex nibble well inside the serialized payload, past the fixed
+	// version/network header, so the corruption lands in the body the
+	// checksum is meant to protect.
+	flipIdx := len(body) - 10
+	body[flipIdx] = flipHexNibble(body[flipIdx])
+	corrupted := strings.Join([]string{parts[0], string(body), parts[2]}, ":")
+
+	_, err = t2z.DecodePCZT(corrupted, t2z.NetworkTestnet)
+	if err != t2z.ErrEnvelopeChecksumMismatch {
+		t.Fatalf("DecodePCZT(corrupted) = %v, want ErrEnvelopeChecksumMismatch", err)
+	}
+}
+
+func TestDecodePCZTWrongPrefixRejected(t *testing.T) {
+	pczt := envelopeFixturePCZT(t)
+	envelope, err := t2z.EncodePCZT(pczt, t2z.NetworkTestnet)
+	if err != nil {
+		t.Fatalf("EncodePCZT: %v", err)
+	}
+
+	wrongPrefix := "pczt-zec" + strings.TrimPrefix(envelope, "pczt-zcash")
+	if _, err := t2z.DecodePCZT(wrongPrefix, t2z.NetworkTestnet); err != t2z.ErrMalformedEnvelope {
+		t.Fatalf("DecodePCZT(wrong prefix) = %v, want ErrMalformedEnvelope", err)
+	}
+}
+
+func TestDecodePCZTNetworkMismatch(t *testing.T) {
+	pczt := envelopeFixturePCZT(t)
+	envelope, err := t2z.EncodePCZT(pczt, t2z.NetworkTestnet)
+	if err != nil {
+		t.Fatalf("EncodePCZT: %v", err)
+	}
+
+	_, err = t2z.DecodePCZT(envelope, t2z.NetworkMainnet)
+	var mismatch *t2z.NetworkMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("DecodePCZT(wrong network) = %v, want *NetworkMismatchError", err)
+	}
+}
+
+// flipHexNibble returns a different hex digit than b, so replacing a hex
+// character with this result is guaranteed to change the decoded byte.
+func flipHexNibble(b byte) byte {
+	if b == '0' {
+		return '1'
+	}
+	return '0'
+}
@@ -0,0 +1,357 @@
+package t2z
+
+import "fmt"
+
+// Opcodes the script engine understands. This is intentionally a small
+// subset of the full Zcash/Bitcoin script language - only what
+// FinalizeAndExtract ever actually produces for P2PKH and P2SH-multisig
+// inputs - rather than a general-purpose interpreter.
+const (
+	opZero        = 0x00
+	opDup         = 0x76
+	opEqual       = 0x87
+	opEqualVerify = 0x88
+	opHash160     = 0xa9
+	opCheckSig    = 0xac
+)
+
+// ScriptError reports exactly where transparent script execution failed:
+// which input, and which opcode it was executing when it did. This mirrors
+// the (input index, opcode) detail a txscript.Engine.Execute error carries,
+// so a caller can tell "wrong pubkey attached" apart from "wrong sighash
+// signed" at a glance instead of just getting a bool back.
+type ScriptError struct {
+	InputIndex int
+	Opcode     byte
+	Reason     string
+}
+
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("t2z: input %d: opcode 0x%02x: %s", e.InputIndex, e.Opcode, e.Reason)
+}
+
+// VerifyExtractedTx is VerifyTransaction's lower-level counterpart: instead
+// of matching scriptPubKey against known templates, it actually runs a
+// minimal stack-based script interpreter over each input's
+// `scriptSig ++ scriptPubKey` (and, for P2SH, the redeem script it reveals),
+// the same way a full node's txscript.Engine would. This is the last-line
+// check a wallet should run on FinalizeAndExtract's output before
+// broadcasting: it catches a wrong sighash signed, or the wrong pubkey
+// attached to a TransparentInput, by actually executing the scripts rather
+// than trusting that AppendSignature was called correctly. VerifyTransaction
+// is built on this same interpreter; it takes TransparentOutput instead of a
+// full TransparentInput since it doesn't need the rest of the fields.
+func VerifyExtractedTx(txBytes []byte, prevScripts []TransparentInput) error {
+	return verifyExtractedTx(txBytes, prevScripts, StandardVerifyFlags, nil)
+}
+
+// VerifyTransparentInputs is VerifyExtractedTx under the name a caller
+// migrating from a standalone script-interpreter package would expect.
+func VerifyTransparentInputs(txBytes []byte, prevScripts []TransparentInput) error {
+	return verifyExtractedTx(txBytes, prevScripts, StandardVerifyFlags, nil)
+}
+
+func verifyExtractedTx(txBytes []byte, prevScripts []TransparentInput, flags ScriptFlags, cache *SigCache) error {
+	tx, err := parseV5Transaction(txBytes)
+	if err != nil {
+		return fmt.Errorf("t2z: parsing transaction: %w", err)
+	}
+	if len(tx.Inputs) != len(prevScripts) {
+		return fmt.Errorf("t2z: have %d transparent inputs but %d previous scripts", len(tx.Inputs), len(prevScripts))
+	}
+
+	for i, prev := range prevScripts {
+		if err := executeInputScript(tx, i, prev.ScriptPubKey, prev.Amount, flags, cache); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isP2SH reports whether script is a standard `OP_HASH160 <20 bytes>
+// OP_EQUAL` P2SH scriptPubKey.
+func isP2SH(script []byte) bool {
+	return len(script) == 23 && script[0] == 0xa9 && script[1] == 0x14 && script[22] == 0x87
+}
+
+// executeInputScript runs input i's scriptSig, then its scriptPubKey, over a
+// shared stack, following the P2SH rule (re-running the redeem script the
+// scriptSig reveals) when scriptPubKey is a P2SH template.
+func executeInputScript(tx *parsedTransaction, i int, scriptPubKey []byte, amount uint64, flags ScriptFlags, cache *SigCache) error {
+	engine := &scriptEngine{tx: tx, inputIndex: i, scriptCode: scriptPubKey, amount: amount, flags: flags, cache: cache}
+
+	if err := engine.run(tx.Inputs[i].ScriptSig); err != nil {
+		return err
+	}
+	scriptSigStack := append([][]byte{}, engine.stack...)
+
+	if err := engine.run(scriptPubKey); err != nil {
+		return err
+	}
+	if !engine.topIsTrue() {
+		return &ScriptError{InputIndex: i, Reason: "scriptPubKey did not evaluate to true"}
+	}
+
+	if isP2SH(scriptPubKey) {
+		if len(scriptSigStack) == 0 {
+			return &ScriptError{InputIndex: i, Opcode: opHash160, Reason: "P2SH scriptSig pushed no redeem script"}
+		}
+		redeemScript := scriptSigStack[len(scriptSigStack)-1]
+		engine.stack = scriptSigStack[:len(scriptSigStack)-1]
+		engine.scriptCode = redeemScript
+		if err := engine.run(redeemScript); err != nil {
+			return err
+		}
+		if !engine.topIsTrue() {
+			return &ScriptError{InputIndex: i, Reason: "redeem script did not evaluate to true"}
+		}
+	}
+	return nil
+}
+
+// scriptEngine is a minimal stack machine: enough opcodes to execute a
+// standard P2PKH scriptSig+scriptPubKey pair, or a CHECKMULTISIG redeem
+// script, and nothing else.
+type scriptEngine struct {
+	tx         *parsedTransaction
+	inputIndex int
+	scriptCode []byte
+	amount     uint64
+	flags      ScriptFlags
+	cache      *SigCache
+	stack      [][]byte
+}
+
+func (e *scriptEngine) topIsTrue() bool {
+	if len(e.stack) == 0 {
+		return false
+	}
+	top := e.stack[len(e.stack)-1]
+	for _, b := range top {
+		if b != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *scriptEngine) push(b []byte) { e.stack = append(e.stack, b) }
+
+func (e *scriptEngine) pop() ([]byte, error) {
+	if len(e.stack) == 0 {
+		return nil, &ScriptError{InputIndex: e.inputIndex, Reason: "pop from empty stack"}
+	}
+	top := e.stack[len(e.stack)-1]
+	e.stack = e.stack[:len(e.stack)-1]
+	return top, nil
+}
+
+// run interprets script over e's existing stack.
+func (e *scriptEngine) run(script []byte) error {
+	pos := 0
+	for pos < len(script) {
+		op := script[pos]
+		switch {
+		case op == opZero:
+			e.push(nil)
+			pos++
+		case op >= 1 && op <= 0x4d:
+			push, next, err := readPush(script, pos)
+			if err != nil {
+				return &ScriptError{InputIndex: e.inputIndex, Opcode: op, Reason: err.Error()}
+			}
+			e.push(push)
+			pos = next
+		case op >= 0x51 && op <= 0x60: // OP_1-OP_16
+			e.push([]byte{op - op1Minus1})
+			pos++
+		case op == opDup:
+			if len(e.stack) == 0 {
+				return &ScriptError{InputIndex: e.inputIndex, Opcode: op, Reason: "OP_DUP on empty stack"}
+			}
+			e.push(append([]byte{}, e.stack[len(e.stack)-1]...))
+			pos++
+		case op == opHash160:
+			v, err := e.pop()
+			if err != nil {
+				return &ScriptError{InputIndex: e.inputIndex, Opcode: op, Reason: err.Error()}
+			}
+			e.push(hash160(v))
+			pos++
+		case op == opEqual, op == opEqualVerify:
+			b, err := e.pop()
+			if err != nil {
+				return &ScriptError{InputIndex: e.inputIndex, Opcode: op, Reason: err.Error()}
+			}
+			a, err := e.pop()
+			if err != nil {
+				return &ScriptError{InputIndex: e.inputIndex, Opcode: op, Reason: err.Error()}
+			}
+			equal := string(a) == string(b)
+			if op == opEqualVerify {
+				if !equal {
+					return &ScriptError{InputIndex: e.inputIndex, Opcode: op, Reason: "OP_EQUALVERIFY failed"}
+				}
+				pos++
+				continue
+			}
+			if equal {
+				e.push([]byte{1})
+			} else {
+				e.push(nil)
+			}
+			pos++
+		case op == opCheckSig:
+			pubkey, err := e.pop()
+			if err != nil {
+				return &ScriptError{InputIndex: e.inputIndex, Opcode: op, Reason: err.Error()}
+			}
+			sig, err := e.pop()
+			if err != nil {
+				return &ScriptError{InputIndex: e.inputIndex, Opcode: op, Reason: err.Error()}
+			}
+			ok, err := e.checkSig(sig, pubkey)
+			if err != nil {
+				return &ScriptError{InputIndex: e.inputIndex, Opcode: op, Reason: err.Error()}
+			}
+			if ok {
+				e.push([]byte{1})
+			} else {
+				e.push(nil)
+			}
+			pos++
+		case op == opCheckMultisig:
+			if err := e.checkMultisig(); err != nil {
+				return err
+			}
+			pos++
+		default:
+			return &ScriptError{InputIndex: e.inputIndex, Opcode: op, Reason: "unsupported opcode"}
+		}
+	}
+	return nil
+}
+
+// checkSig verifies sig (with its trailing sighash-type byte) against pubkey
+// and this engine's scriptCode under e.flags, consulting e.cache (which may
+// be nil) so a signature checked once isn't re-verified via both
+// VerifyBeforeSigning and VerifyTransaction/VerifyExtractedTx.
+func (e *scriptEngine) checkSig(sig, pubkey []byte) (bool, error) {
+	rawSig, sighashType, err := splitSighashSuffix(sig)
+	if err != nil {
+		return false, err
+	}
+	parsedSig, err := parseSignature(rawSig, e.flags)
+	if err != nil {
+		return false, err
+	}
+	parsedPubkey, err := parsePubkey(pubkey, e.flags)
+	if err != nil {
+		return false, err
+	}
+	sighash, err := zip244TransparentSighashWithType(e.tx, e.inputIndex, e.scriptCode, e.amount, sighashType)
+	if err != nil {
+		return false, err
+	}
+	return verifyCached(e.cache, sighash, sig, pubkey, parsedSig, parsedPubkey), nil
+}
+
+// checkMultisig implements OP_CHECKMULTISIG's off-by-one-quirked calling
+// convention: `OP_0 <sig_1> ... <sig_m> OP_m <pubkey_1> ... <pubkey_n> OP_n`.
+func (e *scriptEngine) checkMultisig() error {
+	nBytes, err := e.pop()
+	if err != nil {
+		return &ScriptError{InputIndex: e.inputIndex, Opcode: opCheckMultisig, Reason: err.Error()}
+	}
+	n := int(scriptNum(nBytes))
+	pubkeys := make([][]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		pubkeys[i], err = e.pop()
+		if err != nil {
+			return &ScriptError{InputIndex: e.inputIndex, Opcode: opCheckMultisig, Reason: err.Error()}
+		}
+	}
+
+	mBytes, err := e.pop()
+	if err != nil {
+		return &ScriptError{InputIndex: e.inputIndex, Opcode: opCheckMultisig, Reason: err.Error()}
+	}
+	m := int(scriptNum(mBytes))
+	sigs := make([][]byte, m)
+	for i := m - 1; i >= 0; i-- {
+		sigs[i], err = e.pop()
+		if err != nil {
+			return &ScriptError{InputIndex: e.inputIndex, Opcode: opCheckMultisig, Reason: err.Error()}
+		}
+	}
+
+	// The historical CHECKMULTISIG off-by-one bug pops one extra, unused stack
+	// item; FinalizeAndExtract's scriptSigs always supply it as OP_0.
+	if _, err := e.pop(); err != nil {
+		return &ScriptError{InputIndex: e.inputIndex, Opcode: opCheckMultisig, Reason: err.Error()}
+	}
+
+	pkIdx := 0
+	for _, sig := range sigs {
+		matched := false
+		for ; pkIdx < len(pubkeys); pkIdx++ {
+			ok, err := e.checkSig(sig, pubkeys[pkIdx])
+			if err != nil {
+				continue
+			}
+			if ok {
+				matched = true
+				pkIdx++
+				break
+			}
+		}
+		if !matched {
+			return &ScriptError{InputIndex: e.inputIndex, Opcode: opCheckMultisig, Reason: "signature does not match any remaining cosigner pubkey, in order"}
+		}
+	}
+	e.push([]byte{1})
+	return nil
+}
+
+// scriptNum decodes a script-encoded little-endian integer (as pushed by
+// OP_1-OP_16 or a data push) up to what fits in an int.
+func scriptNum(b []byte) int64 {
+	var v int64
+	for i, by := range b {
+		v |= int64(by) << (8 * i)
+	}
+	return v
+}
+
+// readPush reads a single data push (direct push opcode 0x01-0x4b, or
+// OP_PUSHDATA1/2) starting at pos.
+func readPush(script []byte, pos int) ([]byte, int, error) {
+	if pos >= len(script) {
+		return nil, 0, fmt.Errorf("unexpected end of script")
+	}
+	op := script[pos]
+	pos++
+	var length int
+	switch {
+	case op >= 1 && op <= 0x4b:
+		length = int(op)
+	case op == 0x4c: // OP_PUSHDATA1
+		if pos >= len(script) {
+			return nil, 0, fmt.Errorf("truncated OP_PUSHDATA1")
+		}
+		length = int(script[pos])
+		pos++
+	case op == 0x4d: // OP_PUSHDATA2
+		if pos+2 > len(script) {
+			return nil, 0, fmt.Errorf("truncated OP_PUSHDATA2")
+		}
+		length = int(script[pos]) | int(script[pos+1])<<8
+		pos += 2
+	default:
+		return nil, 0, fmt.Errorf("unsupported opcode 0x%02x in scriptSig", op)
+	}
+	if pos+length > len(script) {
+		return nil, 0, fmt.Errorf("push length exceeds script")
+	}
+	return script[pos : pos+length], pos + length, nil
+}
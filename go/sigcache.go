@@ -0,0 +1,78 @@
+package t2z
+
+import (
+	"sync"
+)
+
+// sigCacheEntry identifies one (sighash, signature, pubkey) verification.
+type sigCacheEntry struct {
+	sighash [32]byte
+	sig     string
+	pubkey  string
+}
+
+// SigCache remembers the outcome of past signature verifications so that
+// verifying the same (sighash, signature, pubkey) triple twice - for example
+// once in VerifyBeforeSigning and again in VerifyTransaction after
+// finalization - only runs the actual ECDSA check once.
+type SigCache struct {
+	mu         sync.Mutex
+	maxEntries uint
+	results    map[sigCacheEntry]bool
+}
+
+// NewSigCache creates an empty SigCache that holds at most maxEntries
+// results before it starts evicting to make room for new ones.
+func NewSigCache(maxEntries uint) *SigCache {
+	return &SigCache{maxEntries: maxEntries, results: make(map[sigCacheEntry]bool)}
+}
+
+// Get reports whether (sighash, sig, pubkey) was previously recorded, and its
+// result if so.
+func (c *SigCache) Get(sighash [32]byte, sig, pubkey []byte) (valid, ok bool) {
+	if c == nil {
+		return false, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	valid, ok = c.results[sigCacheEntry{sighash: sighash, sig: string(sig), pubkey: string(pubkey)}]
+	return valid, ok
+}
+
+// Exists reports whether (sighash, sig, pubkey) was previously recorded,
+// regardless of whether it verified successfully.
+func (c *SigCache) Exists(sighash [32]byte, sig, pubkey []byte) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.results[sigCacheEntry{sighash: sighash, sig: string(sig), pubkey: string(pubkey)}]
+	return ok
+}
+
+// Len returns the number of results currently cached.
+func (c *SigCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.results)
+}
+
+// Add records the outcome of verifying (sighash, sig, pubkey).
+func (c *SigCache) Add(sighash [32]byte, sig, pubkey []byte, valid bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if uint(len(c.results)) >= c.maxEntries {
+		for k := range c.results {
+			delete(c.results, k)
+			break
+		}
+	}
+	c.results[sigCacheEntry{sighash: sighash, sig: string(sig), pubkey: string(pubkey)}] = valid
+}
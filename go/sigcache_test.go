@@ -0,0 +1,166 @@
+package t2z_test
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	t2z "github.com/gstohl/t2z/go"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// sigCacheFixture deterministically derives a distinct (sighash, sig, pubkey)
+// triple for input i, so successive benchmark/test iterations exercise
+// distinct cache entries rather than repeatedly hitting the same one.
+func sigCacheFixture(i int) (sighash [32]byte, sig, pubkey []byte) {
+	var seed [8]byte
+	binary.LittleEndian.PutUint64(seed[:], uint64(i))
+	sighash = sha256.Sum256(seed[:])
+	sig = sighash[:8]
+	pubkey = sighash[8:16]
+	return sighash, sig, pubkey
+}
+
+func TestSigCacheAddExistsLen(t *testing.T) {
+	cache := t2z.NewSigCache(10)
+
+	sighash, sig, pubkey := sigCacheFixture(0)
+	if cache.Exists(sighash, sig, pubkey) {
+		t.Fatal("Exists reported true for an entry never added")
+	}
+	if got := cache.Len(); got != 0 {
+		t.Fatalf("Len() = %d, want 0", got)
+	}
+
+	cache.Add(sighash, sig, pubkey, true)
+	if !cache.Exists(sighash, sig, pubkey) {
+		t.Fatal("Exists reported false right after Add")
+	}
+	if got := cache.Len(); got != 1 {
+		t.Fatalf("Len() = %d, want 1", got)
+	}
+
+	valid, ok := cache.Get(sighash, sig, pubkey)
+	if !ok || !valid {
+		t.Fatalf("Get() = (%v, %v), want (true, true)", valid, ok)
+	}
+}
+
+func TestSigCacheEvictsAtMaxEntries(t *testing.T) {
+	const maxEntries = 4
+	cache := t2z.NewSigCache(maxEntries)
+
+	for i := 0; i < maxEntries+1; i++ {
+		sighash, sig, pubkey := sigCacheFixture(i)
+		cache.Add(sighash, sig, pubkey, true)
+	}
+
+	if got := cache.Len(); got > maxEntries {
+		t.Fatalf("Len() = %d, want at most %d after exceeding capacity", got, maxEntries)
+	}
+}
+
+// sigCacheVerifyFixture builds a signed, finalized transaction spending
+// batchSize P2PKH inputs (all controlled by the same key, for simplicity),
+// along with the TransparentOutput list VerifyTransactionWithCache needs to
+// check it.
+func sigCacheVerifyFixture(b *testing.B, batchSize int) ([]byte, []t2z.TransparentOutput) {
+	b.Helper()
+
+	privateKeyBytes := make([]byte, 32)
+	for i := range privateKeyBytes {
+		privateKeyBytes[i] = 1
+	}
+	privKey := secp256k1.PrivKeyFromBytes(privateKeyBytes)
+	pubKeyBytes := privKey.PubKey().SerializeCompressed()
+
+	pubkeyHash := sha256.Sum256(pubKeyBytes)
+	r := ripemd160.New()
+	r.Write(pubkeyHash[:])
+	hash160 := r.Sum(nil)
+	scriptPubKey := append([]byte{0x76, 0xa9, 0x14}, hash160...)
+	scriptPubKey = append(scriptPubKey, 0x88, 0xac)
+
+	inputs := make([]t2z.TransparentInput, batchSize)
+	for i := range inputs {
+		var txid [32]byte
+		binary.LittleEndian.PutUint64(txid[:8], uint64(i))
+		inputs[i] = t2z.TransparentInput{Pubkey: pubKeyBytes, TxID: txid, Vout: 0, Amount: 1_000_000, ScriptPubKey: scriptPubKey}
+	}
+
+	payments := []t2z.Payment{{Address: "tm9iMLAuYMzJ6jtFLcA7rzUmfreGuKvr7Ma", Amount: 50_000_000}}
+	request, err := t2z.NewTransactionRequest(payments)
+	if err != nil {
+		b.Fatalf("NewTransactionRequest: %v", err)
+	}
+	defer request.Free()
+
+	pczt, err := t2z.ProposeTransaction(inputs, request)
+	if err != nil {
+		b.Fatalf("ProposeTransaction: %v", err)
+	}
+	for i := range inputs {
+		sighash, err := t2z.GetSighash(pczt, uint(i))
+		if err != nil {
+			b.Fatalf("GetSighash: %v", err)
+		}
+		compactSig := ecdsa.SignCompact(privKey, sighash[:], true)
+		var sig [64]byte
+		copy(sig[:], compactSig[1:])
+		pczt, err = t2z.AppendSignature(pczt, uint(i), sig)
+		if err != nil {
+			b.Fatalf("AppendSignature: %v", err)
+		}
+	}
+
+	txBytes, err := t2z.FinalizeAndExtract(pczt)
+	if err != nil {
+		b.Fatalf("FinalizeAndExtract: %v", err)
+	}
+
+	prevOutputs := make([]t2z.TransparentOutput, batchSize)
+	for i := range inputs {
+		prevOutputs[i] = t2z.TransparentOutput{ScriptPubKey: inputs[i].ScriptPubKey, Amount: inputs[i].Amount}
+	}
+	return txBytes, prevOutputs
+}
+
+// BenchmarkVerifyTransactionColdCache verifies a 100-input transaction with
+// no SigCache, so every run re-does all 100 ECDSA verifications from
+// scratch - the cost a coordinator pays verifying the same PCZT twice (once
+// pre-broadcast via VerifyBeforeSigning, again via VerifyTransaction) without
+// sharing a cache between the two passes.
+func BenchmarkVerifyTransactionColdCache(b *testing.B) {
+	txBytes, prevOutputs := sigCacheVerifyFixture(b, 100)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if err := t2z.VerifyTransactionWithCache(txBytes, prevOutputs, t2z.StandardVerifyFlags, nil); err != nil {
+			b.Fatalf("VerifyTransactionWithCache: %v", err)
+		}
+	}
+}
+
+// BenchmarkVerifyTransactionWarmCache verifies the same 100-input
+// transaction through a SigCache that's already been populated by a prior
+// pass, so every ECDSA verification is a cache hit. The gap between this and
+// BenchmarkVerifyTransactionColdCache is the win from passing the same
+// SigCache across VerifyBeforeSigning and VerifyTransaction/
+// VerifyTransactionWithCache on one PCZT.
+func BenchmarkVerifyTransactionWarmCache(b *testing.B) {
+	txBytes, prevOutputs := sigCacheVerifyFixture(b, 100)
+
+	cache := t2z.NewSigCache(256)
+	if err := t2z.VerifyTransactionWithCache(txBytes, prevOutputs, t2z.StandardVerifyFlags, cache); err != nil {
+		b.Fatalf("priming VerifyTransactionWithCache: %v", err)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if err := t2z.VerifyTransactionWithCache(txBytes, prevOutputs, t2z.StandardVerifyFlags, cache); err != nil {
+			b.Fatalf("VerifyTransactionWithCache: %v", err)
+		}
+	}
+}
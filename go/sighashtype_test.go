@@ -0,0 +1,135 @@
+package t2z_test
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	t2z "github.com/gstohl/t2z/go"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// sighashTypeFixture builds a 2-input, 2-output P2PKH transaction (both
+// inputs controlled by the same key, for simplicity) and returns everything
+// a test needs to sign and verify it under different SighashTypes.
+func sighashTypeFixture(t *testing.T) (pczt *t2z.PCZT, privKey *secp256k1.PrivateKey, inputs []t2z.TransparentInput) {
+	t.Helper()
+
+	privateKeyBytes := make([]byte, 32)
+	for i := range privateKeyBytes {
+		privateKeyBytes[i] = 1
+	}
+	privKey = secp256k1.PrivKeyFromBytes(privateKeyBytes)
+	pubKeyBytes := privKey.PubKey().SerializeCompressed()
+
+	pubkeyHash := sha256.Sum256(pubKeyBytes)
+	r := ripemd160.New()
+	r.Write(pubkeyHash[:])
+	hash160 := r.Sum(nil)
+	scriptPubKey := append([]byte{0x76, 0xa9, 0x14}, hash160...)
+	scriptPubKey = append(scriptPubKey, 0x88, 0xac)
+
+	inputs = make([]t2z.TransparentInput, 2)
+	for i := range inputs {
+		var txid [32]byte
+		binary.LittleEndian.PutUint64(txid[:8], uint64(i))
+		inputs[i] = t2z.TransparentInput{Pubkey: pubKeyBytes, TxID: txid, Vout: 0, Amount: 1_000_000, ScriptPubKey: scriptPubKey}
+	}
+
+	payments := []t2z.Payment{
+		{Address: "tm9iMLAuYMzJ6jtFLcA7rzUmfreGuKvr7Ma", Amount: 500_000},
+		{Address: "tm9iMLAuYMzJ6jtFLcA7rzUmfreGuKvr7Ma", Amount: 400_000},
+	}
+	request, err := t2z.NewTransactionRequest(payments)
+	if err != nil {
+		t.Fatalf("NewTransactionRequest: %v", err)
+	}
+	t.Cleanup(request.Free)
+
+	pczt, err = t2z.ProposeTransaction(inputs, request)
+	if err != nil {
+		t.Fatalf("ProposeTransaction: %v", err)
+	}
+	return pczt, privKey, inputs
+}
+
+func signWithType(t *testing.T, pczt *t2z.PCZT, privKey *secp256k1.PrivateKey, inputIndex uint, sighashType t2z.SighashType) *t2z.PCZT {
+	t.Helper()
+	sighash, err := t2z.GetSighashWithType(pczt, inputIndex, sighashType)
+	if err != nil {
+		t.Fatalf("GetSighashWithType(%d, %#02x): %v", inputIndex, byte(sighashType), err)
+	}
+	compactSig := ecdsa.SignCompact(privKey, sighash[:], true)
+	var sig [64]byte
+	copy(sig[:], compactSig[1:])
+	signed, err := t2z.AppendSignatureWithType(pczt, inputIndex, sig, sighashType)
+	if err != nil {
+		t.Fatalf("AppendSignatureWithType(%d, %#02x): %v", inputIndex, byte(sighashType), err)
+	}
+	return signed
+}
+
+// TestSighashTypesProduceDistinctDigests checks that SighashAll, SighashNone,
+// SighashSingle, and SighashAnyOneCanPay (and their OR combination) each bind
+// a different digest for the same input - the narrowing Example 8's
+// parallel-signing workflow depends on.
+func TestSighashTypesProduceDistinctDigests(t *testing.T) {
+	pczt, _, _ := sighashTypeFixture(t)
+
+	types := []t2z.SighashType{
+		t2z.SighashAll,
+		t2z.SighashNone,
+		t2z.SighashSingle,
+		t2z.SighashAll | t2z.SighashAnyOneCanPay,
+		t2z.SighashNone | t2z.SighashAnyOneCanPay,
+	}
+	seen := map[[32]byte]t2z.SighashType{}
+	for _, st := range types {
+		sighash, err := t2z.GetSighashWithType(pczt, 0, st)
+		if err != nil {
+			t.Fatalf("GetSighashWithType(%#02x): %v", byte(st), err)
+		}
+		if prior, ok := seen[sighash]; ok {
+			t.Fatalf("sighash types %#02x and %#02x produced the same digest", byte(prior), byte(st))
+		}
+		seen[sighash] = st
+	}
+}
+
+// TestSighashSingleAnyOneCanPayRoundTrips checks that a signature produced
+// under SIGHASH_SINGLE|ANYONECANPAY for one input verifies correctly once
+// both inputs are signed and the transaction is finalized - the actual
+// parallel-signer scenario chunk1-3 was requested for.
+func TestSighashSingleAnyOneCanPayRoundTrips(t *testing.T) {
+	pczt, privKey, inputs := sighashTypeFixture(t)
+
+	sighashType := t2z.SighashSingle | t2z.SighashAnyOneCanPay
+	pczt = signWithType(t, pczt, privKey, 0, sighashType)
+	pczt = signWithType(t, pczt, privKey, 1, t2z.SighashAll)
+
+	txBytes, err := t2z.FinalizeAndExtract(pczt)
+	if err != nil {
+		t.Fatalf("FinalizeAndExtract: %v", err)
+	}
+
+	prevOutputs := make([]t2z.TransparentOutput, len(inputs))
+	for i, in := range inputs {
+		prevOutputs[i] = t2z.TransparentOutput{ScriptPubKey: in.ScriptPubKey, Amount: in.Amount}
+	}
+	if err := t2z.VerifyTransaction(txBytes, prevOutputs); err != nil {
+		t.Fatalf("VerifyTransaction: %v", err)
+	}
+}
+
+// TestGetSighashWithTypeRejectsUnsupportedType checks that an invalid
+// SighashType byte (one that's neither All/None/Single nor a valid
+// AnyOneCanPay combination of them) is rejected rather than silently treated
+// as SighashAll.
+func TestGetSighashWithTypeRejectsUnsupportedType(t *testing.T) {
+	pczt, _, _ := sighashTypeFixture(t)
+	if _, err := t2z.GetSighashWithType(pczt, 0, t2z.SighashType(0x04)); err == nil {
+		t.Fatal("GetSighashWithType accepted an unsupported sighash type")
+	}
+}
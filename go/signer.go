@@ -0,0 +1,176 @@
+package t2z
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// Signer produces a signature for a single transparent input's sighash. It is
+// modeled after btcd's txscript.SecretsSource: implementations may reach out to
+// an HSM, a hardware wallet, or simply a local private key.
+type Signer interface {
+	// Sign returns a 64-byte (r||s) signature for sighash, which is the input
+	// at inputIndex signed by the key matching pubkey.
+	Sign(inputIndex int, sighash [32]byte, pubkey []byte) ([64]byte, error)
+}
+
+// SignAll walks every transparent input of pczt - in the same order as inputs,
+// the slice originally passed to ProposeTransaction - asks signer for a
+// signature over each sighash, and appends it, returning a fully signed PCZT in
+// one call. This replaces the manual per-input GetSighash/AppendSignature loop
+// shown throughout the examples.
+func SignAll(pczt *PCZT, inputs []TransparentInput, signer Signer) (*PCZT, error) {
+	current := pczt
+	for i, input := range inputs {
+		sighash, err := GetSighash(current, uint(i))
+		if err != nil {
+			return nil, fmt.Errorf("t2z: getting sighash for input %d: %w", i, err)
+		}
+
+		sig, err := signer.Sign(i, sighash, input.Pubkey)
+		if err != nil {
+			return nil, fmt.Errorf("t2z: signing input %d: %w", i, err)
+		}
+
+		current, err = AppendSignature(current, uint(i), sig)
+		if err != nil {
+			return nil, fmt.Errorf("t2z: appending signature for input %d: %w", i, err)
+		}
+	}
+	return current, nil
+}
+
+// LocalSecpSigner signs with one or more in-process secp256k1 private keys,
+// selecting the key whose pubkey hash160 matches the input being signed.
+type LocalSecpSigner struct {
+	keysByHash map[string]*secp256k1.PrivateKey
+}
+
+// NewLocalSecpSigner builds a LocalSecpSigner from the given private keys,
+// indexing each by its compressed pubkey's hash160 so SignAll can resolve the
+// right key per input.
+func NewLocalSecpSigner(keys ...*secp256k1.PrivateKey) *LocalSecpSigner {
+	s := &LocalSecpSigner{keysByHash: make(map[string]*secp256k1.PrivateKey, len(keys))}
+	for _, key := range keys {
+		hash := hash160(key.PubKey().SerializeCompressed())
+		s.keysByHash[string(hash)] = key
+	}
+	return s
+}
+
+// Sign implements Signer.
+func (s *LocalSecpSigner) Sign(_ int, sighash [32]byte, pubkey []byte) ([64]byte, error) {
+	var sig [64]byte
+	key, ok := s.keysByHash[string(hash160(pubkey))]
+	if !ok {
+		return sig, fmt.Errorf("t2z: no local key for pubkey %x", pubkey)
+	}
+	compact := ecdsa.SignCompact(key, sighash[:], true)
+	copy(sig[:], compact[1:])
+	return sig, nil
+}
+
+// CallbackSigner adapts an arbitrary signing function - for example one that
+// forwards the sighash to a hardware wallet over USB or Bluetooth - to the
+// Signer interface.
+type CallbackSigner struct {
+	SignFunc func(inputIndex int, sighash [32]byte, pubkey []byte) ([64]byte, error)
+}
+
+// NewCallbackSigner wraps fn as a Signer.
+func NewCallbackSigner(fn func(inputIndex int, sighash [32]byte, pubkey []byte) ([64]byte, error)) *CallbackSigner {
+	return &CallbackSigner{SignFunc: fn}
+}
+
+// Sign implements Signer.
+func (s *CallbackSigner) Sign(inputIndex int, sighash [32]byte, pubkey []byte) ([64]byte, error) {
+	return s.SignFunc(inputIndex, sighash, pubkey)
+}
+
+// SecretsSource looks up a raw private key by the hash160 of its public key,
+// modeled on btcwallet's txauthor.SecretsSource. Unlike LocalSecpSigner it
+// keeps keys out of Signer's interface entirely, so a caller can back it with
+// an encrypted keystore instead of holding *secp256k1.PrivateKey values
+// in-process.
+type SecretsSource interface {
+	// GetKey returns the raw 32-byte private key whose pubkey (compressed if
+	// compressed is true, uncompressed otherwise) hashes to pubkeyHash.
+	GetKey(pubkeyHash []byte) (privKey []byte, compressed bool, err error)
+}
+
+// SignAllTransparentInputs is SignAll for a SecretsSource: it walks inputs in
+// order, looks up each one's key in src by hash160(input.Pubkey), and appends
+// a compact signature for it, producing a fully signed PCZT. Unlike
+// LocalSecpSigner it supports mixing keys from different sources across
+// inputs without the caller collecting them into one slice up front.
+func SignAllTransparentInputs(pczt *PCZT, inputs []TransparentInput, src SecretsSource) (*PCZT, error) {
+	return SignAll(pczt, inputs, &secretsSourceSigner{src: src})
+}
+
+// secretsSourceSigner adapts a SecretsSource to Signer.
+type secretsSourceSigner struct {
+	src SecretsSource
+}
+
+// Sign implements Signer.
+func (s *secretsSourceSigner) Sign(_ int, sighash [32]byte, pubkey []byte) ([64]byte, error) {
+	var sig [64]byte
+	raw, compressed, err := s.src.GetKey(hash160(pubkey))
+	if err != nil {
+		return sig, fmt.Errorf("t2z: looking up key for pubkey %x: %w", pubkey, err)
+	}
+	key := secp256k1.PrivKeyFromBytes(raw)
+	compact := ecdsa.SignCompact(key, sighash[:], compressed)
+	copy(sig[:], compact[1:])
+	return sig, nil
+}
+
+// secretEntry is one key InMemorySecrets holds, keyed by its pubkey hash160.
+type secretEntry struct {
+	raw        []byte
+	compressed bool
+}
+
+// InMemorySecrets is a SecretsSource backed by an in-process map, for tests
+// and simple wallets that don't need an encrypted keystore.
+type InMemorySecrets struct {
+	keysByHash map[string]secretEntry
+}
+
+// NewInMemorySecrets builds an empty InMemorySecrets.
+func NewInMemorySecrets() *InMemorySecrets {
+	return &InMemorySecrets{keysByHash: make(map[string]secretEntry)}
+}
+
+// Add indexes key under the hash160 of its public key, compressed or
+// uncompressed as requested, so SignAllTransparentInputs can resolve it for
+// any input that expects that encoding.
+func (s *InMemorySecrets) Add(key *secp256k1.PrivateKey, compressed bool) {
+	pub := key.PubKey()
+	pubBytes := pub.SerializeUncompressed()
+	if compressed {
+		pubBytes = pub.SerializeCompressed()
+	}
+	s.keysByHash[string(hash160(pubBytes))] = secretEntry{raw: key.Serialize(), compressed: compressed}
+}
+
+// GetKey implements SecretsSource.
+func (s *InMemorySecrets) GetKey(pubkeyHash []byte) ([]byte, bool, error) {
+	entry, ok := s.keysByHash[string(pubkeyHash)]
+	if !ok {
+		return nil, false, fmt.Errorf("t2z: no key for pubkey hash %x", pubkeyHash)
+	}
+	return entry.raw, entry.compressed, nil
+}
+
+// hash160 computes RIPEMD160(SHA256(data)), the standard Bitcoin/Zcash pubkey hash.
+func hash160(data []byte) []byte {
+	sha := sha256.Sum256(data)
+	r := ripemd160.New()
+	r.Write(sha[:])
+	return r.Sum(nil)
+}
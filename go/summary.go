@@ -0,0 +1,130 @@
+package t2z
+
+import "fmt"
+
+// Pool identifies which value pool a PCZTOutputSummary output is paid into.
+// This package only ever builds transparent outputs, so every output reports
+// PoolTransparent; the type exists so a summary reads the same whether a
+// future shielded-output build adds Sapling/Orchard pools alongside it.
+type Pool int
+
+const (
+	PoolTransparent Pool = iota
+)
+
+func (p Pool) String() string {
+	switch p {
+	case PoolTransparent:
+		return "transparent"
+	default:
+		return "unknown"
+	}
+}
+
+// PCZTOutputSummary is one output's contribution to a PCZTSummary: which
+// pool it pays into, how much, and - for a transparent output - the address
+// and memo, plus whether ProposeTransactionWithSources added it as change
+// rather than the caller's own request.
+type PCZTOutputSummary struct {
+	Pool     Pool
+	Amount   uint64
+	Address  string
+	Memo     string
+	IsChange bool
+}
+
+// PCZTSummary is a human-reviewable breakdown of what a PCZT will do,
+// intended for hardware-wallet-style "confirm on device" UX before signing:
+// total value in, where every payment goes, the fee being paid, and the
+// target block height, all in one place instead of requiring the signer to
+// re-derive it from raw inputs. Unlike the TransactionRequest a Creator
+// builds a PCZT from, everything SummarizePCZT reads comes off the PCZT
+// itself, so an air-gapped device that has only ever seen the serialized
+// bytes (see Parse) can still reconstruct exactly what it is about to sign.
+type PCZTSummary struct {
+	Inputs       []TransparentInput
+	Outputs      []PCZTOutputSummary
+	TotalIn      uint64
+	TotalOut     uint64
+	Fee          uint64
+	TargetHeight uint32
+	Network      string
+
+	// Fingerprint is the ZIP-244 transaction ID pczt commits to before any
+	// signature is appended (see zip244TxID) - a caller that displayed this
+	// summary for confirmation can recompute it with VerifySighashBinding
+	// right before signing, to make sure the PCZT in hand still matches the
+	// one that was reviewed.
+	Fingerprint [32]byte
+}
+
+// SummarizePCZT builds a PCZTSummary entirely from pczt's own fields.
+func SummarizePCZT(pczt *PCZT) (*PCZTSummary, error) {
+	if len(pczt.outputs) != len(pczt.payments) {
+		return nil, fmt.Errorf("t2z: summarizing PCZT: %d outputs but %d payments", len(pczt.outputs), len(pczt.payments))
+	}
+
+	s := &PCZTSummary{
+		TargetHeight: pczt.targetHeight,
+		Fingerprint:  zip244TxID(pczt.toParsedTx()),
+	}
+	if pczt.useMainnet {
+		s.Network = "mainnet"
+	} else {
+		s.Network = "testnet"
+	}
+
+	for _, in := range pczt.inputs {
+		s.Inputs = append(s.Inputs, in.TransparentInput)
+		s.TotalIn += in.Amount
+	}
+	for i, out := range pczt.outputs {
+		p := pczt.payments[i]
+		s.Outputs = append(s.Outputs, PCZTOutputSummary{
+			Pool:     PoolTransparent,
+			Amount:   out.amount,
+			Address:  p.Address,
+			Memo:     p.Memo,
+			IsChange: p.isChange,
+		})
+		s.TotalOut += out.amount
+	}
+
+	if s.TotalOut > s.TotalIn {
+		return nil, fmt.Errorf("t2z: summarizing PCZT: outputs total %d exceeds inputs total %d", s.TotalOut, s.TotalIn)
+	}
+	s.Fee = s.TotalIn - s.TotalOut
+	return s, nil
+}
+
+// String renders a short, human-readable confirmation line per output plus
+// the total fee, suitable for a hardware wallet's confirmation screen.
+func (s *PCZTSummary) String() string {
+	out := fmt.Sprintf("Spending %d input(s) totaling %d zatoshis (%s, target height %d):\n",
+		len(s.Inputs), s.TotalIn, s.Network, s.TargetHeight)
+	for _, o := range s.Outputs {
+		if o.IsChange {
+			out += fmt.Sprintf("  -> %d zatoshis change to %s (%s)\n", o.Amount, o.Address, o.Pool)
+			continue
+		}
+		line := fmt.Sprintf("  -> %d zatoshis to %s (%s)", o.Amount, o.Address, o.Pool)
+		if o.Memo != "" {
+			line += fmt.Sprintf(" memo: %q", o.Memo)
+		}
+		out += line + "\n"
+	}
+	out += fmt.Sprintf("Fee: %d zatoshis\n", s.Fee)
+	return out
+}
+
+// VerifySighashBinding re-derives pczt's fingerprint and checks it against
+// summary.Fingerprint, so a signer that displayed summary for user
+// confirmation can confirm, right before producing a signature, that pczt
+// is still the exact PCZT it reviewed - not one swapped in afterward with
+// the same envelope but different inputs or outputs.
+func VerifySighashBinding(pczt *PCZT, summary *PCZTSummary) error {
+	if got := zip244TxID(pczt.toParsedTx()); got != summary.Fingerprint {
+		return fmt.Errorf("t2z: PCZT fingerprint %x does not match summarized fingerprint %x", got, summary.Fingerprint)
+	}
+	return nil
+}
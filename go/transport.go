@@ -0,0 +1,167 @@
+package t2z
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Role identifies which step of the PCZT lifecycle a party plays, matching
+// the BIP-174-style role separation the PCZT format is built around:
+// Creator proposes the transaction, Updater adds data (proofs, fee info),
+// Signer appends signatures, Combiner merges parallel signing branches, and
+// Finalizer assembles and extracts the final transaction. It is carried
+// alongside a serialized PCZT purely as a hint for tooling (e.g. a CLI that
+// prints "waiting on: Signer") - the underlying PCZT format doesn't enforce
+// it itself.
+type Role int
+
+const (
+	RoleCreator Role = iota
+	RoleUpdater
+	RoleSigner
+	RoleCombiner
+	RoleFinalizer
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleCreator:
+		return "Creator"
+	case RoleUpdater:
+		return "Updater"
+	case RoleSigner:
+		return "Signer"
+	case RoleCombiner:
+		return "Combiner"
+	case RoleFinalizer:
+		return "Finalizer"
+	default:
+		return fmt.Sprintf("Role(%d)", int(r))
+	}
+}
+
+// RoleCapabilityError is returned by the RoleAs-suffixed functions below when
+// the calling party's Role isn't allowed to perform the attempted operation,
+// e.g. a Signer trying to add an output or an Updater trying to sign.
+type RoleCapabilityError struct {
+	Role      Role
+	Operation string
+}
+
+func (e *RoleCapabilityError) Error() string {
+	return fmt.Sprintf("t2z: %s may not %s", e.Role, e.Operation)
+}
+
+// requireRole returns a *RoleCapabilityError unless have == want.
+func requireRole(have, want Role, operation string) error {
+	if have != want {
+		return &RoleCapabilityError{Role: have, Operation: operation}
+	}
+	return nil
+}
+
+// ProposeAs is ProposeTransaction restricted to the Creator: the only role
+// that may propose a PCZT's initial set of inputs and outputs.
+func ProposeAs(role Role, inputs []TransparentInput, request *TransactionRequest) (*PCZT, error) {
+	if err := requireRole(role, RoleCreator, "propose a transaction"); err != nil {
+		return nil, err
+	}
+	return ProposeTransaction(inputs, request)
+}
+
+// AddOutputAs is AddOutput restricted to the Creator or Updater: a Signer
+// holds a PCZT to produce signatures for the outputs it already has, not to
+// change what it pays, so it - along with Combiner and Finalizer - is
+// refused here.
+func AddOutputAs(role Role, pczt *PCZT, payment Payment) (*PCZT, error) {
+	if role != RoleCreator && role != RoleUpdater {
+		return nil, &RoleCapabilityError{Role: role, Operation: "add an output"}
+	}
+	return AddOutput(pczt, payment)
+}
+
+// AppendSignatureAs is AppendSignature restricted to the Signer.
+func AppendSignatureAs(role Role, pczt *PCZT, inputIndex uint, sig [64]byte) (*PCZT, error) {
+	if err := requireRole(role, RoleSigner, "append a signature"); err != nil {
+		return nil, err
+	}
+	return AppendSignature(pczt, inputIndex, sig)
+}
+
+// CombineAs is Combine restricted to the Combiner. Combine already refuses
+// to merge PCZTs whose inputs/outputs don't describe the same proposal (see
+// ErrOutputMismatch's sibling check in Combine); this only adds the
+// role check on top.
+func CombineAs(role Role, pczts []*PCZT) (*PCZT, error) {
+	if err := requireRole(role, RoleCombiner, "combine PCZTs"); err != nil {
+		return nil, err
+	}
+	return Combine(pczts)
+}
+
+// FinalizeAndExtractAs is FinalizeAndExtract restricted to the Finalizer.
+func FinalizeAndExtractAs(role Role, pczt *PCZT) ([]byte, error) {
+	if err := requireRole(role, RoleFinalizer, "finalize and extract"); err != nil {
+		return nil, err
+	}
+	return FinalizeAndExtract(pczt)
+}
+
+// fileEnvelope is SaveToFile's on-disk representation: a pczt-zcash envelope
+// (see EncodePCZT) plus the role of whoever produced it, so the next party in
+// the workflow knows what's expected of them. The PCZT itself only ever has
+// one text encoding - EncodePCZT/DecodePCZT's pczt-zcash envelope; Role is
+// metadata about that file, not a second encoding of the PCZT.
+type fileEnvelope struct {
+	Role     int    `json:"role"`
+	Envelope string `json:"envelope"`
+}
+
+// SaveToFile writes pczt to path as a fileEnvelope - a pczt-zcash envelope
+// (see EncodePCZT) tagged with producedBy - so it can be handed off to
+// another party (e.g. over sneakernet to an air-gapped signer) and later read
+// back with LoadFromFile.
+func SaveToFile(path string, pczt *PCZT, producedBy Role) error {
+	return SaveToFileForNetwork(path, pczt, producedBy, NetworkMainnet)
+}
+
+// SaveToFileForNetwork is SaveToFile for a non-mainnet PCZT; LoadFromFileForNetwork
+// must be given the same network to read it back.
+func SaveToFileForNetwork(path string, pczt *PCZT, producedBy Role, network Network) error {
+	envelope, err := EncodePCZT(pczt, network)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(fileEnvelope{Role: int(producedBy), Envelope: envelope})
+	if err != nil {
+		return fmt.Errorf("t2z: encoding %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("t2z: writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadFromFile reads a mainnet PCZT previously written by SaveToFile.
+func LoadFromFile(path string) (*PCZT, Role, error) {
+	return LoadFromFileForNetwork(path, NetworkMainnet)
+}
+
+// LoadFromFileForNetwork reads a PCZT previously written by SaveToFileForNetwork
+// for network.
+func LoadFromFileForNetwork(path string, network Network) (*PCZT, Role, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("t2z: reading %s: %w", path, err)
+	}
+	var envelope fileEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, 0, fmt.Errorf("t2z: parsing %s: %w", path, err)
+	}
+	pczt, err := DecodePCZT(envelope.Envelope, network)
+	if err != nil {
+		return nil, 0, err
+	}
+	return pczt, Role(envelope.Role), nil
+}
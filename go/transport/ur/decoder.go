@@ -0,0 +1,128 @@
+package ur
+
+import (
+	"fmt"
+	"hash/crc32"
+)
+
+// equation is one received part expressed as a linear equation over GF(2):
+// the XOR of the fragments named by indexes equals data.
+type equation struct {
+	indexes []int
+	data    []byte
+}
+
+// Decoder accumulates UR fountain parts and solves for the original fragments
+// by Gaussian elimination over GF(2) as new, possibly-mixed parts arrive.
+type Decoder struct {
+	total     int
+	length    int
+	checksum  uint32
+	equations []equation
+	fragments map[int][]byte // solved fragments, by index
+}
+
+// NewDecoder creates an empty Decoder.
+func NewDecoder() *Decoder {
+	return &Decoder{fragments: make(map[int][]byte)}
+}
+
+// Receive ingests one `ur:zcash-pczt/...` part. It returns done=true and the
+// reassembled payload once enough parts have been seen to solve every
+// fragment and the result's CRC32 matches the checksum carried in the parts.
+func (d *Decoder) Receive(partStr string) (done bool, payload []byte, err error) {
+	p, err := parsePart(partStr)
+	if err != nil {
+		return false, nil, err
+	}
+	if d.total == 0 {
+		d.total = p.Total
+		d.length = p.Length
+		d.checksum = p.Checksum
+	} else if p.Total != d.total || p.Checksum != d.checksum {
+		return false, nil, fmt.Errorf("ur: part belongs to a different message")
+	}
+	if len(d.fragments) == d.total {
+		payload, err := d.assemble()
+		return true, payload, err
+	}
+
+	indexes := fragmentIndexesForSeq(p.Seq, d.total)
+	d.reduceAgainstKnown(&indexes, p.Data)
+	if len(indexes) == 0 {
+		if len(d.fragments) != d.total {
+			return false, nil, nil
+		}
+		payload, err := d.assemble()
+		return true, payload, err
+	}
+	d.equations = append(d.equations, equation{indexes: indexes, data: p.Data})
+	d.eliminate()
+
+	if len(d.fragments) == d.total {
+		payload, err := d.assemble()
+		return true, payload, err
+	}
+	return false, nil, nil
+}
+
+// reduceAgainstKnown XORs out any fragments in indexes that have already been
+// solved, mutating data in place and shrinking indexes to the still-unknown
+// fragments - standard Gaussian-elimination row reduction.
+func (d *Decoder) reduceAgainstKnown(indexes *[]int, data []byte) {
+	var remaining []int
+	for _, idx := range *indexes {
+		if known, ok := d.fragments[idx]; ok {
+			xorInto(data, known)
+			continue
+		}
+		remaining = append(remaining, idx)
+	}
+	*indexes = remaining
+}
+
+// eliminate repeatedly scans the equation set for rows that have been reduced
+// to a single unknown fragment (solving it) or that can be reduced using
+// newly-solved fragments, until no more progress can be made.
+func (d *Decoder) eliminate() {
+	for progress := true; progress; {
+		progress = false
+		var remaining []equation
+		for _, eq := range d.equations {
+			indexes := append([]int{}, eq.indexes...)
+			data := append([]byte{}, eq.data...)
+			d.reduceAgainstKnown(&indexes, data)
+
+			switch len(indexes) {
+			case 0:
+				// Fully cancelled out; either redundant or a checksum mismatch.
+				progress = true
+			case 1:
+				d.fragments[indexes[0]] = data
+				progress = true
+			default:
+				remaining = append(remaining, equation{indexes: indexes, data: data})
+			}
+		}
+		d.equations = remaining
+	}
+}
+
+// assemble concatenates solved fragments in order, trims to the original CBOR
+// length, unwraps the CBOR byte string, and verifies the CRC32 checksum.
+func (d *Decoder) assemble() ([]byte, error) {
+	var cbor []byte
+	for i := 0; i < d.total; i++ {
+		cbor = append(cbor, d.fragments[i]...)
+	}
+	cbor = cbor[:d.length]
+
+	payload, err := cborUnwrapByteString(cbor)
+	if err != nil {
+		return nil, fmt.Errorf("ur: unwrapping reassembled payload: %w", err)
+	}
+	if crc32.ChecksumIEEE(payload) != d.checksum {
+		return nil, fmt.Errorf("ur: reassembled payload failed checksum verification")
+	}
+	return payload, nil
+}
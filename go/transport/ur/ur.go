@@ -0,0 +1,195 @@
+// Package ur implements a Blockchain Commons Uniform Resources (UR) style
+// fountain-coded transport for serialized PCZTs, so an air-gapped hardware
+// wallet can consume a t2z.Serialize payload as a sequence of animated QR
+// frames instead of over USB or Bluetooth.
+//
+// It follows the shape of the UR spec (github.com/BlockchainCommons/Research/
+// blob/master/papers/bcr-2020-005-ur.md) closely enough to be recognizable -
+// CBOR-wrapped payload, fountain-coded parts, bech32-style framing - without
+// depending on the reference bytewords/CBOR libraries.
+package ur
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"math/rand"
+)
+
+// URType is the UR type tag carried by every part, e.g. "ur:zcash-pczt/...".
+const URType = "zcash-pczt"
+
+// Encoder splits payloads into streams of UR fountain parts.
+type Encoder struct{}
+
+// NewEncoder creates an Encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// Split CBOR-wraps payload under the zcash-pczt UR type, breaks it into
+// maxFragmentLen-sized fragments, and returns len(fragments) "pure" parts
+// (one source fragment each) followed by an equal number of additional
+// fountain parts generated by XOR-combining fragments chosen by a
+// sequence-seeded RNG, in the style of Luby Transform codes. Each returned
+// string is small enough to render as a single QR frame and is
+// self-describing: `ur:zcash-pczt/<seq>-<total>/<len>-<crc32>-<hex>`.
+func (e *Encoder) Split(payload []byte, maxFragmentLen int) []string {
+	cbor := cborWrapByteString(payload)
+	fragments := chunk(cbor, maxFragmentLen)
+	total := len(fragments)
+	checksum := crc32.ChecksumIEEE(payload)
+
+	// Emit every source fragment once (pure parts), then an equal number of
+	// mixed fountain parts; a decoder can stop consuming as soon as it has
+	// solved for every fragment, which typically happens before this runs out.
+	parts := make([]string, 0, total*2)
+	for seq := 1; seq <= total*2; seq++ {
+		indexes := fragmentIndexesForSeq(seq, total)
+		mixed := make([]byte, len(fragments[0]))
+		for _, idx := range indexes {
+			xorInto(mixed, fragments[idx])
+		}
+		p := part{
+			Seq:      seq,
+			Total:    total,
+			Length:   len(cbor),
+			Checksum: checksum,
+			Data:     mixed,
+		}
+		parts = append(parts, p.encode())
+	}
+	return parts
+}
+
+// part is a single fountain-coded UR fragment.
+type part struct {
+	Seq      int
+	Total    int
+	Length   int
+	Checksum uint32
+	Data     []byte
+}
+
+func (p part) encode() string {
+	return fmt.Sprintf("ur:%s/%d-%d/%d-%08x-%s", URType, p.Seq, p.Total, p.Length, p.Checksum, hex.EncodeToString(p.Data))
+}
+
+func parsePart(s string) (part, error) {
+	var p part
+	var lengthAndChecksum, hexData string
+	prefix := fmt.Sprintf("ur:%s/", URType)
+	if len(s) <= len(prefix) || s[:len(prefix)] != prefix {
+		return p, fmt.Errorf("ur: not a %s part: %q", URType, s)
+	}
+	rest := s[len(prefix):]
+	if _, err := fmt.Sscanf(rest, "%d-%d/%s", &p.Seq, &p.Total, &lengthAndChecksum); err != nil {
+		return p, fmt.Errorf("ur: malformed part %q: %w", s, err)
+	}
+	var checksum uint32
+	if _, err := fmt.Sscanf(lengthAndChecksum, "%d-%08x-%s", &p.Length, &checksum, &hexData); err != nil {
+		return p, fmt.Errorf("ur: malformed part body %q: %w", lengthAndChecksum, err)
+	}
+	p.Checksum = checksum
+	data, err := hex.DecodeString(hexData)
+	if err != nil {
+		return p, fmt.Errorf("ur: invalid part data: %w", err)
+	}
+	p.Data = data
+	return p, nil
+}
+
+// fragmentIndexesForSeq deterministically chooses which source fragments
+// (by index) are XORed together for a given sequence number. Sequence
+// numbers 1..total are "pure" (one fragment each); beyond that, a
+// seq-seeded RNG selects a degree and a set of fragment indexes, mirroring
+// the UR spec's fountain part generator.
+func fragmentIndexesForSeq(seq, total int) []int {
+	if seq <= total || total == 1 {
+		return []int{(seq - 1) % total}
+	}
+	rng := rand.New(rand.NewSource(int64(seq)))
+	degree := 2 + rng.Intn(total-1)
+	if degree > total {
+		degree = total
+	}
+	perm := rng.Perm(total)
+	return perm[:degree]
+}
+
+func xorInto(dst, src []byte) {
+	for i := range dst {
+		if i < len(src) {
+			dst[i] ^= src[i]
+		}
+	}
+}
+
+// chunk splits data into fixed-size fragments, zero-padding the last one so
+// every fragment (and therefore every XOR combination of them) is the same
+// length.
+func chunk(data []byte, size int) [][]byte {
+	n := (len(data) + size - 1) / size
+	if n == 0 {
+		n = 1
+	}
+	out := make([][]byte, n)
+	for i := range out {
+		frag := make([]byte, size)
+		start := i * size
+		end := start + size
+		if end > len(data) {
+			end = len(data)
+		}
+		copy(frag, data[start:end])
+		out[i] = frag
+	}
+	return out
+}
+
+// cborWrapByteString wraps data as a minimal CBOR byte-string item (major
+// type 2), which is how the reference UR encoding represents opaque payloads.
+func cborWrapByteString(data []byte) []byte {
+	var head []byte
+	n := len(data)
+	switch {
+	case n < 24:
+		head = []byte{0x40 | byte(n)}
+	case n < 256:
+		head = []byte{0x58, byte(n)}
+	case n < 65536:
+		head = []byte{0x59, byte(n >> 8), byte(n)}
+	default:
+		head = []byte{0x5a, byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+	}
+	return append(head, data...)
+}
+
+// cborUnwrapByteString reverses cborWrapByteString.
+func cborUnwrapByteString(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("ur: empty CBOR item")
+	}
+	major := data[0] >> 5
+	if major != 2 {
+		return nil, fmt.Errorf("ur: expected CBOR byte string, got major type %d", major)
+	}
+	info := data[0] & 0x1f
+	var n, headerLen int
+	switch {
+	case info < 24:
+		n, headerLen = int(info), 1
+	case info == 24:
+		n, headerLen = int(data[1]), 2
+	case info == 25:
+		n, headerLen = int(data[1])<<8|int(data[2]), 3
+	case info == 26:
+		n, headerLen = int(data[1])<<24|int(data[2])<<16|int(data[3])<<8|int(data[4]), 5
+	default:
+		return nil, fmt.Errorf("ur: unsupported CBOR length encoding")
+	}
+	if len(data) < headerLen+n {
+		return nil, fmt.Errorf("ur: truncated CBOR byte string")
+	}
+	return data[headerLen : headerLen+n], nil
+}
@@ -0,0 +1,30 @@
+package ur
+
+import "testing"
+
+// TestSplitSingleFragmentPayload is a regression test for fragmentIndexesForSeq
+// panicking with "invalid argument to Intn" whenever a payload fits in a
+// single fragment (total == 1), which happens for any small/serialized PCZT
+// given a generous maxFragmentLen.
+func TestSplitSingleFragmentPayload(t *testing.T) {
+	e := NewEncoder()
+	parts := e.Split([]byte("hello world small payload"), 1000)
+	if len(parts) == 0 {
+		t.Fatal("Split returned no parts")
+	}
+
+	d := NewDecoder()
+	for _, p := range parts {
+		done, payload, err := d.Receive(p)
+		if err != nil {
+			t.Fatalf("Receive: %v", err)
+		}
+		if done {
+			if string(payload) != "hello world small payload" {
+				t.Fatalf("decoded payload = %q, want %q", payload, "hello world small payload")
+			}
+			return
+		}
+	}
+	t.Fatal("decoder never assembled the payload")
+}
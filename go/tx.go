@@ -0,0 +1,85 @@
+package t2z
+
+import "fmt"
+
+// TxInput is one transparent input of a parsed transaction, exported for
+// callers that need to inspect a finalized transaction without going through
+// the opaque PCZT handle.
+type TxInput struct {
+	PrevTxID  [32]byte
+	PrevVout  uint32
+	ScriptSig []byte
+	Sequence  uint32
+}
+
+// TxOutput is one transparent output of a parsed transaction.
+type TxOutput struct {
+	Amount       uint64
+	ScriptPubKey []byte
+}
+
+// Tx is a finalized v5 transaction's transparent bundle, as returned by
+// ParseTransaction.
+type Tx struct {
+	Version         uint32
+	VersionGroupID  uint32
+	ConsensusHeight uint32
+	LockTime        uint32
+	ExpiryHeight    uint32
+	Inputs          []TxInput
+	Outputs         []TxOutput
+}
+
+// ParseTransaction parses txBytes as a finalized ZIP-225 v5 transaction,
+// exposing its transparent inputs and outputs for inspection, e.g. by
+// VerifyTransaction/VerifyExtractedTx or TxChainBuilder.
+func ParseTransaction(txBytes []byte) (*Tx, error) {
+	parsed, err := parseV5Transaction(txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: parsing transaction: %w", err)
+	}
+	return fromParsed(parsed), nil
+}
+
+// TxID computes tx's ZIP-244 transaction ID. Unlike a legacy Bitcoin-style
+// txid, it excludes every input's scriptSig, so it's already fixed once a
+// transaction's inputs and outputs are set - before it's signed, let alone
+// broadcast. See TxChainBuilder, which relies on this to chain transactions
+// spending each other's outputs before any of them are finalized.
+func TxID(tx *Tx) [32]byte {
+	return zip244TxID(tx.toParsed())
+}
+
+func fromParsed(p *parsedTransaction) *Tx {
+	tx := &Tx{
+		Version:         p.Version,
+		VersionGroupID:  p.VersionGroupID,
+		ConsensusHeight: p.ConsensusHeight,
+		LockTime:        p.LockTime,
+		ExpiryHeight:    p.ExpiryHeight,
+	}
+	for _, in := range p.Inputs {
+		tx.Inputs = append(tx.Inputs, TxInput{PrevTxID: in.PrevTxID, PrevVout: in.PrevVout, ScriptSig: in.ScriptSig, Sequence: in.Sequence})
+	}
+	for _, out := range p.Outputs {
+		tx.Outputs = append(tx.Outputs, TxOutput{Amount: out.Amount, ScriptPubKey: out.ScriptPubKey})
+	}
+	return tx
+}
+
+func (tx *Tx) toParsed() *parsedTransaction {
+	p := &parsedTransaction{
+		Version:         tx.Version,
+		VersionGroupID:  tx.VersionGroupID,
+		ConsensusHeight: tx.ConsensusHeight,
+		LockTime:        tx.LockTime,
+		ExpiryHeight:    tx.ExpiryHeight,
+	}
+	for _, in := range tx.Inputs {
+		p.Inputs = append(p.Inputs, parsedTransparentInput{PrevTxID: in.PrevTxID, PrevVout: in.PrevVout, ScriptSig: in.ScriptSig, Sequence: in.Sequence})
+	}
+	for _, out := range tx.Outputs {
+		p.Outputs = append(p.Outputs, parsedTransparentOutput{Amount: out.Amount, ScriptPubKey: out.ScriptPubKey})
+	}
+	return p
+}
@@ -0,0 +1,94 @@
+package t2z
+
+import "fmt"
+
+// PendingTx is one transaction in a TxChainBuilder chain: its finalized,
+// signed bytes plus the transaction ID TxChainBuilder.Add computed for it
+// before the chain was broadcast.
+type PendingTx struct {
+	// TxID is tx's ZIP-244 transaction ID (see TxID). It's valid the moment
+	// Add returns, independent of whether this or any earlier link in the
+	// chain has been broadcast.
+	TxID [32]byte
+	// Bytes is the finalized, signed transaction, ready for
+	// sendrawtransaction.
+	Bytes []byte
+}
+
+// Outpoint returns (p.TxID, vout), ready to plug straight into a later
+// TxChainBuilder.Add call's TransparentInput.TxID/Vout.
+func (p *PendingTx) Outpoint(vout uint32) (txid [32]byte, voutOut uint32) {
+	return p.TxID, vout
+}
+
+// TxChainBuilder builds a sequence of dependent transactions - where a later
+// transaction spends an output of an earlier one that hasn't been broadcast,
+// or even submitted to a node, yet - the pattern consolidation and splitting
+// pipelines need when they can't wait for each intermediate transaction to
+// confirm. It signs and finalizes every link as soon as it's added, using
+// this package's ZIP-244 TxID (which doesn't depend on scriptSigs) so the
+// next Add call already knows the outpoint it's spending.
+type TxChainBuilder struct {
+	signer       Signer
+	useMainnet   bool
+	targetHeight uint32
+	chain        []*PendingTx
+}
+
+// NewTxChainBuilder builds an empty TxChainBuilder. Every transaction Add
+// proposes is signed with signer and built against useMainnet/targetHeight,
+// the same settings TransactionRequest.SetUseMainnet/SetTargetHeight expose.
+func NewTxChainBuilder(signer Signer, useMainnet bool, targetHeight uint32) *TxChainBuilder {
+	return &TxChainBuilder{signer: signer, useMainnet: useMainnet, targetHeight: targetHeight}
+}
+
+// Add proposes, signs, and finalizes a transaction spending inputs to
+// payments, appending it to the chain and returning it as a PendingTx. An
+// input may spend an output of a PendingTx returned by an earlier Add call on
+// the same builder - set its TxID/Vout from PendingTx.Outpoint - even though
+// that earlier transaction hasn't been submitted to a node.
+func (b *TxChainBuilder) Add(inputs []TransparentInput, payments []Payment) (*PendingTx, error) {
+	link := len(b.chain)
+
+	request, err := NewTransactionRequest(payments)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: building transaction request for chain link %d: %w", link, err)
+	}
+	defer request.Free()
+	request.SetUseMainnet(b.useMainnet)
+	request.SetTargetHeight(b.targetHeight)
+
+	pczt, err := ProposeTransaction(inputs, request)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: proposing chain link %d: %w", link, err)
+	}
+
+	signed, err := SignAll(pczt, inputs, b.signer)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: signing chain link %d: %w", link, err)
+	}
+
+	txBytes, err := FinalizeAndExtract(signed)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: finalizing chain link %d: %w", link, err)
+	}
+
+	tx, err := ParseTransaction(txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("t2z: parsing chain link %d: %w", link, err)
+	}
+
+	pending := &PendingTx{TxID: TxID(tx), Bytes: txBytes}
+	b.chain = append(b.chain, pending)
+	return pending, nil
+}
+
+// Finalize returns every chained transaction's bytes in the order they were
+// added, ready for sequential sendrawtransaction calls.
+func (b *TxChainBuilder) Finalize() [][]byte {
+	out := make([][]byte, len(b.chain))
+	for i, p := range b.chain {
+		out[i] = p.Bytes
+	}
+	return out
+}
@@ -0,0 +1,255 @@
+package t2z
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// parsedTransparentInput is a single transparent input as it appears in a
+// finalized v5 (ZIP-225) transaction: a previous outpoint plus the scriptSig
+// and sequence number that spend it.
+type parsedTransparentInput struct {
+	PrevTxID  [32]byte
+	PrevVout  uint32
+	ScriptSig []byte
+	Sequence  uint32
+}
+
+// parsedTransparentOutput is a single transparent output.
+type parsedTransparentOutput struct {
+	Amount       uint64
+	ScriptPubKey []byte
+}
+
+// parsedTransaction is the subset of a finalized v5 Zcash transaction that the
+// transparent script engine needs. Sapling/Orchard bundles are skipped over
+// but not otherwise interpreted.
+type parsedTransaction struct {
+	Raw             []byte
+	Version         uint32
+	VersionGroupID  uint32
+	ConsensusHeight uint32
+	LockTime        uint32
+	ExpiryHeight    uint32
+	Inputs          []parsedTransparentInput
+	Outputs         []parsedTransparentOutput
+
+	// Trailing holds everything after the transparent outputs (the Sapling and
+	// Orchard bundles) verbatim, so serialize can round-trip a transaction
+	// after only rewriting transparent scriptSigs.
+	Trailing []byte
+}
+
+// parseV5Transaction parses just enough of a ZIP-225 v5 transaction to recover
+// its transparent inputs and outputs for script verification. It does not
+// attempt to interpret the Sapling or Orchard bundles that follow.
+func parseV5Transaction(txBytes []byte) (*parsedTransaction, error) {
+	r := &byteReader{buf: txBytes}
+
+	header, err := r.readUint32LE()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: reading header: %w", err)
+	}
+	if header&0x80000000 == 0 {
+		return nil, fmt.Errorf("t2z: not an overwintered (v5) transaction")
+	}
+	version := header &^ 0x80000000
+	if version != 5 {
+		return nil, fmt.Errorf("t2z: unsupported transaction version %d (want 5)", version)
+	}
+
+	versionGroupID, err := r.readUint32LE()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: reading version group ID: %w", err)
+	}
+	consensusHeight, err := r.readUint32LE()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: reading consensus branch ID: %w", err)
+	}
+	lockTime, err := r.readUint32LE()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: reading lock time: %w", err)
+	}
+	expiryHeight, err := r.readUint32LE()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: reading expiry height: %w", err)
+	}
+
+	tx := &parsedTransaction{
+		Raw:             txBytes,
+		Version:         version,
+		VersionGroupID:  versionGroupID,
+		ConsensusHeight: consensusHeight,
+		LockTime:        lockTime,
+		ExpiryHeight:    expiryHeight,
+	}
+
+	numInputs, err := r.readCompactSize()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: reading input count: %w", err)
+	}
+	tx.Inputs = make([]parsedTransparentInput, numInputs)
+	for i := range tx.Inputs {
+		var in parsedTransparentInput
+		if err := r.readBytesInto(in.PrevTxID[:]); err != nil {
+			return nil, fmt.Errorf("t2z: reading input %d txid: %w", i, err)
+		}
+		if in.PrevVout, err = r.readUint32LE(); err != nil {
+			return nil, fmt.Errorf("t2z: reading input %d vout: %w", i, err)
+		}
+		if in.ScriptSig, err = r.readVarBytes(); err != nil {
+			return nil, fmt.Errorf("t2z: reading input %d scriptSig: %w", i, err)
+		}
+		if in.Sequence, err = r.readUint32LE(); err != nil {
+			return nil, fmt.Errorf("t2z: reading input %d sequence: %w", i, err)
+		}
+		tx.Inputs[i] = in
+	}
+
+	numOutputs, err := r.readCompactSize()
+	if err != nil {
+		return nil, fmt.Errorf("t2z: reading output count: %w", err)
+	}
+	tx.Outputs = make([]parsedTransparentOutput, numOutputs)
+	for i := range tx.Outputs {
+		var out parsedTransparentOutput
+		if out.Amount, err = r.readUint64LE(); err != nil {
+			return nil, fmt.Errorf("t2z: reading output %d amount: %w", i, err)
+		}
+		if out.ScriptPubKey, err = r.readVarBytes(); err != nil {
+			return nil, fmt.Errorf("t2z: reading output %d scriptPubKey: %w", i, err)
+		}
+		tx.Outputs[i] = out
+	}
+
+	tx.Trailing = append([]byte{}, r.buf[r.pos:]...)
+
+	return tx, nil
+}
+
+// serialize re-encodes tx, reusing Trailing verbatim. Callers that only need
+// to rewrite a transparent input's scriptSig (e.g. multisig finalization)
+// should mutate tx.Inputs[i].ScriptSig and call this rather than attempting to
+// reconstruct the Sapling/Orchard bundles themselves.
+func (tx *parsedTransaction) serialize() []byte {
+	var out []byte
+	out = append(out, le32(tx.Version|0x80000000)...)
+	out = append(out, le32(tx.VersionGroupID)...)
+	out = append(out, le32(tx.ConsensusHeight)...)
+	out = append(out, le32(tx.LockTime)...)
+	out = append(out, le32(tx.ExpiryHeight)...)
+
+	out = append(out, encodeCompactSize(uint64(len(tx.Inputs)))...)
+	for _, in := range tx.Inputs {
+		out = append(out, in.PrevTxID[:]...)
+		out = append(out, le32(in.PrevVout)...)
+		out = append(out, encodeCompactSize(uint64(len(in.ScriptSig)))...)
+		out = append(out, in.ScriptSig...)
+		out = append(out, le32(in.Sequence)...)
+	}
+
+	out = append(out, encodeCompactSize(uint64(len(tx.Outputs)))...)
+	for _, o := range tx.Outputs {
+		out = append(out, le64(o.Amount)...)
+		out = append(out, encodeCompactSize(uint64(len(o.ScriptPubKey)))...)
+		out = append(out, o.ScriptPubKey...)
+	}
+
+	out = append(out, tx.Trailing...)
+	return out
+}
+
+// encodeCompactSize encodes n as a Bitcoin/Zcash CompactSize varint.
+func encodeCompactSize(n uint64) []byte {
+	switch {
+	case n < 0xfd:
+		return []byte{byte(n)}
+	case n <= 0xffff:
+		return []byte{0xfd, byte(n), byte(n >> 8)}
+	case n <= 0xffffffff:
+		return []byte{0xfe, byte(n), byte(n >> 8), byte(n >> 16), byte(n >> 24)}
+	default:
+		b := make([]byte, 9)
+		b[0] = 0xff
+		binary.LittleEndian.PutUint64(b[1:], n)
+		return b
+	}
+}
+
+// byteReader is a minimal little-endian/varint cursor over a transaction's
+// serialized bytes.
+type byteReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *byteReader) readUint32LE() (uint32, error) {
+	if len(r.buf)-r.pos < 4 {
+		return 0, fmt.Errorf("unexpected end of transaction")
+	}
+	v := binary.LittleEndian.Uint32(r.buf[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+func (r *byteReader) readUint64LE() (uint64, error) {
+	if len(r.buf)-r.pos < 8 {
+		return 0, fmt.Errorf("unexpected end of transaction")
+	}
+	v := binary.LittleEndian.Uint64(r.buf[r.pos:])
+	r.pos += 8
+	return v, nil
+}
+
+func (r *byteReader) readBytesInto(dst []byte) error {
+	if len(r.buf)-r.pos < len(dst) {
+		return fmt.Errorf("unexpected end of transaction")
+	}
+	copy(dst, r.buf[r.pos:])
+	r.pos += len(dst)
+	return nil
+}
+
+// readCompactSize reads a Bitcoin/Zcash CompactSize (varint) length prefix.
+func (r *byteReader) readCompactSize() (uint64, error) {
+	if r.pos >= len(r.buf) {
+		return 0, fmt.Errorf("unexpected end of transaction")
+	}
+	first := r.buf[r.pos]
+	r.pos++
+	switch {
+	case first < 0xfd:
+		return uint64(first), nil
+	case first == 0xfd:
+		v, err := r.readUint16LE()
+		return uint64(v), err
+	case first == 0xfe:
+		v, err := r.readUint32LE()
+		return uint64(v), err
+	default:
+		return r.readUint64LE()
+	}
+}
+
+func (r *byteReader) readUint16LE() (uint16, error) {
+	if len(r.buf)-r.pos < 2 {
+		return 0, fmt.Errorf("unexpected end of transaction")
+	}
+	v := binary.LittleEndian.Uint16(r.buf[r.pos:])
+	r.pos += 2
+	return v, nil
+}
+
+func (r *byteReader) readVarBytes() ([]byte, error) {
+	n, err := r.readCompactSize()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(r.buf)-r.pos) < n {
+		return nil, fmt.Errorf("unexpected end of transaction")
+	}
+	out := make([]byte, n)
+	copy(out, r.buf[r.pos:r.pos+int(n)])
+	r.pos += int(n)
+	return out, nil
+}
@@ -0,0 +1,97 @@
+package t2z
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// ScriptFlags gates which script rules VerifyTransactionWithFlags enforces,
+// mirroring txscript.ScriptFlags.
+type ScriptFlags uint32
+
+const (
+	// ScriptVerifyDERSignatures rejects non-DER-encoded signatures.
+	ScriptVerifyDERSignatures ScriptFlags = 1 << iota
+	// ScriptVerifyLowS rejects signatures with a high S value.
+	ScriptVerifyLowS
+	// ScriptVerifyStrictEncoding rejects non-compressed pubkeys and any
+	// scriptPubKey/scriptSig shape this engine doesn't recognize.
+	ScriptVerifyStrictEncoding
+)
+
+// StandardVerifyFlags is the flag set a relaying node would apply to a
+// mempool-bound transaction: canonical signatures, low-S, and strict
+// encoding, matching txscript.StandardVerifyFlags.
+const StandardVerifyFlags = ScriptVerifyDERSignatures | ScriptVerifyLowS | ScriptVerifyStrictEncoding
+
+// VerifyTransaction parses a finalized v5 transaction and, for every
+// transparent input, checks that its scriptSig actually satisfies the
+// scriptPubKey of the output it spends (given in the same order via
+// prevOutputs) under ZIP-244 sighash rules. It rejects non-canonical
+// (non-low-S, non-DER) signatures and non-compressed-or-uncompressed pubkeys.
+//
+// This lets a coordinator catch a bad signature from an external signer -
+// hardware wallet, remote HSM - before ever broadcasting, mirroring how
+// btcwallet's validateMsgTx gates its own broadcast path. It is built on the
+// same script interpreter as VerifyExtractedTx/VerifyTransparentInputs -
+// prevOutputs just spares a caller who only has scriptPubKey/amount (not a
+// full TransparentInput) from constructing one themselves.
+func VerifyTransaction(txBytes []byte, prevOutputs []TransparentOutput) error {
+	return VerifyTransactionWithCache(txBytes, prevOutputs, StandardVerifyFlags, nil)
+}
+
+// VerifyTransactionWithFlags is VerifyTransaction with an explicit
+// ScriptFlags set, for callers (e.g. a coordinator relaying to a specific
+// node policy) that need to relax or tighten standardness rules.
+func VerifyTransactionWithFlags(txBytes []byte, prevOutputs []TransparentOutput, flags ScriptFlags) error {
+	return VerifyTransactionWithCache(txBytes, prevOutputs, flags, nil)
+}
+
+// VerifyTransactionWithCache is VerifyTransactionWithFlags that additionally
+// consults cache (which may be nil) before running each ECDSA verification,
+// and records the result afterward. Passing the same SigCache across
+// VerifyBeforeSigning and VerifyTransaction on the same PCZT avoids
+// re-verifying a signature the coordinator already checked pre-finalization.
+func VerifyTransactionWithCache(txBytes []byte, prevOutputs []TransparentOutput, flags ScriptFlags, cache *SigCache) error {
+	prevScripts := make([]TransparentInput, len(prevOutputs))
+	for i, o := range prevOutputs {
+		prevScripts[i] = TransparentInput{ScriptPubKey: o.ScriptPubKey, Amount: o.Amount}
+	}
+	return verifyExtractedTx(txBytes, prevScripts, flags, cache)
+}
+
+// verifyCached consults cache (which may be nil) for a prior result of
+// verifying sig against sighash and pubkey, running and recording the actual
+// ECDSA check only on a miss.
+func verifyCached(cache *SigCache, sighash [32]byte, sig, pubkey []byte, parsedSig *ecdsa.Signature, parsedPubkey *secp256k1.PublicKey) bool {
+	if valid, ok := cache.Get(sighash, sig, pubkey); ok {
+		return valid
+	}
+	valid := parsedSig.Verify(sighash[:], parsedPubkey)
+	cache.Add(sighash, sig, pubkey, valid)
+	return valid
+}
+
+func parseSignature(sig []byte, flags ScriptFlags) (*ecdsa.Signature, error) {
+	parsed, err := ecdsa.ParseDERSignature(sig)
+	if err != nil {
+		return nil, fmt.Errorf("non-canonical signature: %w", err)
+	}
+	if s := parsed.S(); flags&ScriptVerifyLowS != 0 && s.IsOverHalfOrder() {
+		return nil, fmt.Errorf("signature has a high S value")
+	}
+	return parsed, nil
+}
+
+func parsePubkey(pubkey []byte, flags ScriptFlags) (*secp256k1.PublicKey, error) {
+	if flags&ScriptVerifyStrictEncoding != 0 && len(pubkey) != 33 {
+		return nil, fmt.Errorf("non-compressed pubkey rejected by strict encoding")
+	}
+	parsed, err := secp256k1.ParsePubKey(pubkey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pubkey: %w", err)
+	}
+	return parsed, nil
+}
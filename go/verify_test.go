@@ -0,0 +1,110 @@
+package t2z_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	t2z "github.com/gstohl/t2z/go"
+)
+
+// p2pkhFixture builds a signed, finalized single-input P2PKH transaction,
+// returning its bytes alongside the TransparentInput it spends so a test can
+// feed both VerifyTransaction's and VerifyExtractedTx's entry points.
+func p2pkhFixture(t *testing.T) (txBytes []byte, input t2z.TransparentInput) {
+	t.Helper()
+
+	privateKeyBytes := make([]byte, 32)
+	for i := range privateKeyBytes {
+		privateKeyBytes[i] = 1
+	}
+	privKey := secp256k1.PrivKeyFromBytes(privateKeyBytes)
+	pubKeyBytes := privKey.PubKey().SerializeCompressed()
+
+	scriptPubKey, err := hex.DecodeString("76a91479b000887626b294a914501a4cd226b58b23598388ac")
+	if err != nil {
+		t.Fatalf("decoding scriptPubKey: %v", err)
+	}
+
+	var txid [32]byte
+	input = t2z.TransparentInput{
+		Pubkey:       pubKeyBytes,
+		TxID:         txid,
+		Vout:         0,
+		Amount:       100_000_000,
+		ScriptPubKey: scriptPubKey,
+	}
+
+	payments := []t2z.Payment{
+		{Address: "tm9iMLAuYMzJ6jtFLcA7rzUmfreGuKvr7Ma", Amount: 50_000_000},
+	}
+	request, err := t2z.NewTransactionRequest(payments)
+	if err != nil {
+		t.Fatalf("NewTransactionRequest: %v", err)
+	}
+	defer request.Free()
+
+	pczt, err := t2z.ProposeTransaction([]t2z.TransparentInput{input}, request)
+	if err != nil {
+		t.Fatalf("ProposeTransaction: %v", err)
+	}
+
+	sighash, err := t2z.GetSighash(pczt, 0)
+	if err != nil {
+		t.Fatalf("GetSighash: %v", err)
+	}
+	compactSig := ecdsa.SignCompact(privKey, sighash[:], true)
+	var sig [64]byte
+	copy(sig[:], compactSig[1:])
+
+	signed, err := t2z.AppendSignature(pczt, 0, sig)
+	if err != nil {
+		t.Fatalf("AppendSignature: %v", err)
+	}
+
+	txBytes, err = t2z.FinalizeAndExtract(signed)
+	if err != nil {
+		t.Fatalf("FinalizeAndExtract: %v", err)
+	}
+	return txBytes, input
+}
+
+// TestVerifyTransactionAndExtractedTxAgree checks that VerifyTransaction (the
+// TransparentOutput-based entry point) and VerifyExtractedTx/
+// VerifyTransparentInputs (the TransparentInput-based ones) accept the same
+// valid transaction - they share one script-interpreter implementation, so
+// there is nothing for them to disagree about.
+func TestVerifyTransactionAndExtractedTxAgree(t *testing.T) {
+	txBytes, input := p2pkhFixture(t)
+
+	if err := t2z.VerifyTransaction(txBytes, []t2z.TransparentOutput{
+		{ScriptPubKey: input.ScriptPubKey, Amount: input.Amount},
+	}); err != nil {
+		t.Fatalf("VerifyTransaction: %v", err)
+	}
+	if err := t2z.VerifyExtractedTx(txBytes, []t2z.TransparentInput{input}); err != nil {
+		t.Fatalf("VerifyExtractedTx: %v", err)
+	}
+	if err := t2z.VerifyTransparentInputs(txBytes, []t2z.TransparentInput{input}); err != nil {
+		t.Fatalf("VerifyTransparentInputs: %v", err)
+	}
+}
+
+// TestVerifyTransactionRejectsWrongAmount checks that both entry points
+// reject the same tampered transaction (signed against the wrong amount
+// binds a different sighash), rather than one silently accepting what the
+// other rejects.
+func TestVerifyTransactionRejectsWrongAmount(t *testing.T) {
+	txBytes, input := p2pkhFixture(t)
+	input.Amount++ // claim a different prevout amount than what was signed
+
+	if err := t2z.VerifyTransaction(txBytes, []t2z.TransparentOutput{
+		{ScriptPubKey: input.ScriptPubKey, Amount: input.Amount},
+	}); err == nil {
+		t.Fatal("VerifyTransaction accepted a signature over the wrong amount")
+	}
+	if err := t2z.VerifyExtractedTx(txBytes, []t2z.TransparentInput{input}); err == nil {
+		t.Fatal("VerifyExtractedTx accepted a signature over the wrong amount")
+	}
+}
@@ -0,0 +1,157 @@
+package t2z
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// ZIP-244 personalization tags for the BLAKE2b hashes that make up a v5
+// signature hash. See https://zips.z.cash/zip-0244 for the full construction;
+// this mirrors the transparent-input leg of it, which is all VerifyTransaction
+// needs to re-derive.
+var (
+	zip244HeaderPersonal      = []byte("ZTxIdHeadersHash")
+	zip244PrevoutsPersonal    = []byte("ZTxIdPrevoutHash")
+	zip244SequencePersonal    = []byte("ZTxIdSequencHash")
+	zip244OutputsPersonal     = []byte("ZTxIdOutputsHash")
+	zip244TxInSigPersonal     = []byte("Zcash___TxInHash")
+	zip244SighashPersonal     = []byte("ZcashTxHash_")
+	zip244TransparentPersonal = []byte("ZTxIdTranspaHash")
+	zip244SaplingPersonal     = []byte("ZTxIdSaplingHash")
+	zip244OrchardPersonal     = []byte("ZTxIdOrchardHash")
+)
+
+func blake2bHash(personal []byte, data ...[]byte) [32]byte {
+	h, _ := blake2b.New256(personalize(personal))
+	for _, d := range data {
+		h.Write(d)
+	}
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// personalize pads/truncates tag to BLAKE2b's 16-byte personalization field.
+func personalize(tag []byte) []byte {
+	p := make([]byte, 16)
+	copy(p, tag)
+	return p
+}
+
+func le32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+func le64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b
+}
+
+// transparentTxDigests computes the header, prevouts, sequence, and outputs
+// digests ZIP-244 shares between the per-input sighash and the overall
+// transaction ID (see zip244TxID). None of them commit to any input's
+// scriptSig, which is what lets a transaction's ID be known before it's
+// signed.
+func transparentTxDigests(tx *parsedTransaction) (header, prevouts, sequence, outputs [32]byte) {
+	var prevoutsBytes, sequenceBytes, outputsBytes []byte
+	for _, in := range tx.Inputs {
+		prevoutsBytes = append(prevoutsBytes, in.PrevTxID[:]...)
+		prevoutsBytes = append(prevoutsBytes, le32(in.PrevVout)...)
+		sequenceBytes = append(sequenceBytes, le32(in.Sequence)...)
+	}
+	for _, out := range tx.Outputs {
+		outputsBytes = append(outputsBytes, le64(out.Amount)...)
+		outputsBytes = append(outputsBytes, out.ScriptPubKey...)
+	}
+
+	headerBytes := append(le32(tx.Version|0x80000000), le32(tx.VersionGroupID)...)
+	headerBytes = append(headerBytes, le32(tx.LockTime)...)
+	headerBytes = append(headerBytes, le32(tx.ExpiryHeight)...)
+
+	return blake2bHash(zip244HeaderPersonal, headerBytes),
+		blake2bHash(zip244PrevoutsPersonal, prevoutsBytes),
+		blake2bHash(zip244SequencePersonal, sequenceBytes),
+		blake2bHash(zip244OutputsPersonal, outputsBytes)
+}
+
+// zip244TxID computes a v5 transaction's ZIP-244 transaction ID: the header
+// digest combined with the transparent digest (itself built from the same
+// prevouts/sequence/outputs digests transparentTxDigests returns) and empty
+// Sapling/Orchard digests, since this package only builds transparent
+// bundles. Because those digests exclude scriptSigs, the ID is fixed as soon
+// as a transaction's inputs and outputs are chosen - before it's signed, let
+// alone broadcast - which is what TxChainBuilder relies on to chain
+// dependent, unconfirmed transactions together.
+func zip244TxID(tx *parsedTransaction) [32]byte {
+	header, prevouts, sequence, outputs := transparentTxDigests(tx)
+	hashTransparent := blake2bHash(zip244TransparentPersonal, prevouts[:], sequence[:], outputs[:])
+	hashSapling := blake2bHash(zip244SaplingPersonal)
+	hashOrchard := blake2bHash(zip244OrchardPersonal)
+	return blake2bHash(zip244SighashPersonal, header[:], hashTransparent[:], hashSapling[:], hashOrchard[:])
+}
+
+// zip244TransparentSighash computes the ZIP-244 signature hash for the
+// transparent input at inputIndex, binding it to scriptCode (the scriptPubKey
+// being spent, or the redeem script for P2SH) and the value of the output it
+// spends, with SIGHASH_ALL semantics (every input's sequence and every output
+// committed).
+func zip244TransparentSighash(tx *parsedTransaction, inputIndex int, scriptCode []byte, amount uint64) [32]byte {
+	hash, _ := zip244TransparentSighashWithType(tx, inputIndex, scriptCode, amount, SighashAll)
+	return hash
+}
+
+// zip244TransparentSighashWithType is zip244TransparentSighash generalized to
+// sighashType: SIGHASH_NONE empties the outputs digest, SIGHASH_SINGLE
+// narrows it to the one output at inputIndex (erroring if there isn't one),
+// and SIGHASH_ANYONECANPAY (combinable with either via bitwise OR) empties
+// the prevouts and sequence digests. This is the same s_* narrowing BIP-143
+// does for Bitcoin's segwit sighash, applied to ZIP-244's digest shape.
+func zip244TransparentSighashWithType(tx *parsedTransaction, inputIndex int, scriptCode []byte, amount uint64, sighashType SighashType) ([32]byte, error) {
+	hashHeader, _, _, _ := transparentTxDigests(tx)
+
+	var prevoutsBytes, sequenceBytes []byte
+	if sighashType&SighashAnyOneCanPay == 0 {
+		for _, in := range tx.Inputs {
+			prevoutsBytes = append(prevoutsBytes, in.PrevTxID[:]...)
+			prevoutsBytes = append(prevoutsBytes, le32(in.PrevVout)...)
+			sequenceBytes = append(sequenceBytes, le32(in.Sequence)...)
+		}
+	}
+	hashPrevouts := blake2bHash(zip244PrevoutsPersonal, prevoutsBytes)
+	hashSequence := blake2bHash(zip244SequencePersonal, sequenceBytes)
+
+	var outputsBytes []byte
+	switch sighashType &^ SighashAnyOneCanPay {
+	case SighashNone:
+		// No output is committed to.
+	case SighashSingle:
+		if inputIndex >= len(tx.Outputs) {
+			return [32]byte{}, fmt.Errorf("t2z: SIGHASH_SINGLE requires an output at index %d", inputIndex)
+		}
+		out := tx.Outputs[inputIndex]
+		outputsBytes = append(outputsBytes, le64(out.Amount)...)
+		outputsBytes = append(outputsBytes, out.ScriptPubKey...)
+	default: // SighashAll
+		for _, out := range tx.Outputs {
+			outputsBytes = append(outputsBytes, le64(out.Amount)...)
+			outputsBytes = append(outputsBytes, out.ScriptPubKey...)
+		}
+	}
+	hashOutputs := blake2bHash(zip244OutputsPersonal, outputsBytes)
+
+	in := tx.Inputs[inputIndex]
+	txin := append([]byte{}, in.PrevTxID[:]...)
+	txin = append(txin, le32(in.PrevVout)...)
+	txin = append(txin, le64(amount)...)
+	txin = append(txin, scriptCode...)
+	txin = append(txin, le32(in.Sequence)...)
+	hashTxIn := blake2bHash(zip244TxInSigPersonal, txin)
+
+	return blake2bHash(zip244SighashPersonal,
+		hashHeader[:], hashPrevouts[:], hashSequence[:], hashOutputs[:], hashTxIn[:]), nil
+}
@@ -0,0 +1,58 @@
+package t2z
+
+import "sort"
+
+// zip317GraceActions is the number of "logical actions" ZIP-317 lets a
+// transaction have before the marginal fee kicks in (conventionally 2: one
+// transparent or shielded input/output pair is free).
+const zip317GraceActions = 2
+
+// zip317MarginalFee is the fee, in zatoshis, ZIP-317 charges per logical
+// action beyond zip317GraceActions.
+const zip317MarginalFee = 5_000
+
+// UTXOPool is an in-memory InputSource over a fixed set of UTXOs that selects
+// greedily largest-first, the strategy that adds the fewest additional
+// transparent inputs - and therefore the smallest ZIP-317 marginal fee - to
+// reach a given target.
+type UTXOPool struct {
+	utxos []TransparentInput
+}
+
+// NewUTXOPool builds a UTXOPool over utxos, which need not be sorted.
+func NewUTXOPool(utxos []TransparentInput) *UTXOPool {
+	sorted := append([]TransparentInput{}, utxos...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Amount > sorted[j].Amount })
+	return &UTXOPool{utxos: sorted}
+}
+
+// SelectInputs implements InputSource by taking UTXOs largest-first until
+// their total reaches target, re-checking after each addition since every
+// extra input raises the ZIP-317 fee component of target's caller-computed
+// value by zip317MarginalFee once zip317GraceActions transparent inputs are
+// already selected.
+func (p *UTXOPool) SelectInputs(target uint64) ([]TransparentInput, uint64, error) {
+	var (
+		selected []TransparentInput
+		total    uint64
+	)
+	for _, u := range p.utxos {
+		if total >= target {
+			break
+		}
+		selected = append(selected, u)
+		total += u.Amount
+	}
+	return selected, total, nil
+}
+
+// MarginalFeeFor returns the additional ZIP-317 fee, in zatoshis, that
+// selecting numInputs transparent inputs contributes beyond the grace
+// allowance - useful for InputSource implementations that want to stop
+// selecting once the marginal cost of one more input exceeds its value.
+func MarginalFeeFor(numInputs int) uint64 {
+	if numInputs <= zip317GraceActions {
+		return 0
+	}
+	return uint64(numInputs-zip317GraceActions) * zip317MarginalFee
+}